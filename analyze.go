@@ -0,0 +1,80 @@
+package genpass
+
+import (
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// Hashcat's built-in mask charsets.
+const (
+	hashcatLower   = "abcdefghijklmnopqrstuvwxyz"
+	hashcatUpper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	hashcatDigit   = "0123456789"
+	hashcatSpecial = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// Analysis describes the structure of a password the way a cracker sees it,
+// to estimate the effort of a brute-force attack with external tools.
+type Analysis struct {
+	// Mask is the hashcat mask matching the password, one placeholder per
+	// character: ?l, ?u, ?d, and ?s for ASCII lowercase letters, uppercase
+	// letters, digits, and specials, and ?b for each byte of the UTF-8
+	// encoding of other characters.
+	Mask string
+	// Keyspace is the number of candidates the mask covers, which is the
+	// number of guesses needed to exhaust it.
+	Keyspace *big.Int
+	// Bits is the base-2 logarithm of Keyspace, the bits of work needed to
+	// exhaust the mask.
+	Bits float64
+	// BruteForceKeyspace is the number of candidates of a plain brute-force
+	// attack over all printable ASCII characters (?a) at the password's
+	// length, which doesn't need to know the mask. Non-ASCII characters count
+	// as ?b per byte.
+	BruteForceKeyspace *big.Int
+	// BruteForceBits is the base-2 logarithm of BruteForceKeyspace.
+	BruteForceBits float64
+}
+
+// Analyze returns the hashcat mask of password and the keyspaces of a mask
+// attack and a plain brute-force attack on it. The result is an upper bound
+// on the work needed to crack the password: dictionary attacks and attacks
+// that model how people choose passwords can be much faster, so it doesn't
+// replace [Generator.Entropy] for generated secrets.
+func Analyze(password string) Analysis {
+	var mask strings.Builder
+	keyspace, brute := big.NewInt(1), big.NewInt(1)
+	for i := 0; i < len(password); {
+		r, n := utf8.DecodeRuneInString(password[i:])
+		i += n
+		var size, bruteSize int64 = 256, 95
+		switch {
+		case strings.ContainsRune(hashcatLower, r):
+			mask.WriteString("?l")
+			size = int64(len(hashcatLower))
+		case strings.ContainsRune(hashcatUpper, r):
+			mask.WriteString("?u")
+			size = int64(len(hashcatUpper))
+		case strings.ContainsRune(hashcatDigit, r):
+			mask.WriteString("?d")
+			size = int64(len(hashcatDigit))
+		case strings.ContainsRune(hashcatSpecial, r):
+			mask.WriteString("?s")
+			size = int64(len(hashcatSpecial))
+		default:
+			mask.WriteString(strings.Repeat("?b", n))
+			size = 1 << (8 * n)
+			bruteSize = size
+		}
+		keyspace.Mul(keyspace, big.NewInt(size))
+		brute.Mul(brute, big.NewInt(bruteSize))
+	}
+	return Analysis{
+		Mask:               mask.String(),
+		Keyspace:           keyspace,
+		Bits:               log2Int(keyspace),
+		BruteForceKeyspace: brute,
+		BruteForceBits:     log2Int(brute),
+	}
+}
@@ -0,0 +1,211 @@
+package genpass
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditRecord is an entry of an [AuditLog]. It describes a generated secret
+// and the parameters it was generated with, but not the secret itself.
+type AuditRecord struct {
+	// Seq numbers the records of a log from 1.
+	Seq int `json:"seq"`
+	// Time is when the secret was generated, in UTC.
+	Time time.Time `json:"time"`
+	// Policy is the name of the policy the generator implements, as set with
+	// [WithPolicyName] or [Policy.Options], if any.
+	Policy string `json:"policy,omitempty"`
+	// Passphrase reports whether the secret is a passphrase.
+	Passphrase bool `json:"passphrase"`
+	// Length is the number of characters, or words for passphrases.
+	Length int `json:"length"`
+	// Alphabet is the number of characters in the charset, or words in the
+	// wordlist for passphrases. It is 0 for per-position charsets.
+	Alphabet int `json:"alphabet"`
+	// Required lists the classes every password must contain.
+	Required []string `json:"required,omitempty"`
+	// Entropy is the entropy of the configuration in bits.
+	Entropy float64 `json:"entropy"`
+	// Fingerprint is the hex-encoded SHA-256 hash of the secret.
+	Fingerprint string `json:"fingerprint"`
+	// Prev is the Hash of the previous record, or empty for the first one.
+	Prev string `json:"prev"`
+	// Hash is the hex-encoded SHA-256 hash of the record's JSON encoding
+	// without the Hash field, which chains it to every record before it.
+	Hash string `json:"hash,omitempty"`
+}
+
+// hash returns the hash of r, ignoring its Hash field.
+func (r AuditRecord) hash() (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ErrAuditTampered is returned by [VerifyAuditLog] when a record doesn't match
+// its hash or doesn't follow the previous record.
+var ErrAuditTampered = errors.New("genpass: audit log has been modified")
+
+// AuditLog writes a tamper-evident transcript of generated secrets as JSON
+// lines. Each [AuditRecord] includes the hash of the one before it, so
+// changing, removing, or reordering records breaks the chain, which
+// [VerifyAuditLog] detects. Only the end of the log can be removed unnoticed;
+// keep a copy of the last hash elsewhere to detect that too.
+//
+// An AuditLog is safe for concurrent use, so several generators can share one.
+type AuditLog struct {
+	mu   sync.Mutex
+	w    io.Writer
+	seq  int
+	prev string
+}
+
+// NewAuditLog returns an audit log that writes a new chain of records to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// OpenAuditLog opens the audit log file at path for appending, creating it if
+// necessary. The existing records are verified first, and new records continue
+// their chain. The caller should close the returned file when done.
+func OpenAuditLog(path string) (*AuditLog, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	last, err := verifyAuditLog(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &AuditLog{w: f, seq: last.Seq, prev: last.Hash}, f, nil
+}
+
+// Record fills in the sequence number, time, and hashes of r and appends it
+// to the log.
+func (l *AuditLog) Record(r AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r.Seq = l.seq + 1
+	r.Time = time.Now().UTC()
+	r.Prev = l.prev
+	hash, err := r.hash()
+	if err != nil {
+		return err
+	}
+	r.Hash = hash
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		return &SinkError{Sink: "audit", Err: err}
+	}
+	l.seq, l.prev = r.Seq, r.Hash
+	return nil
+}
+
+// VerifyAuditLog checks the hash chain of an audit log and returns the number
+// of records in it. It returns an error matching [ErrAuditTampered] if the
+// chain is broken.
+func VerifyAuditLog(r io.Reader) (int, error) {
+	last, err := verifyAuditLog(r)
+	return last.Seq, err
+}
+
+// verifyAuditLog checks the hash chain of an audit log and returns its last
+// record.
+func verifyAuditLog(r io.Reader) (AuditRecord, error) {
+	var last AuditRecord
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return last, fmt.Errorf("genpass: audit log line %d: %w", line, err)
+		}
+		hash, err := rec.hash()
+		if err != nil {
+			return last, err
+		}
+		switch {
+		case rec.Hash != hash:
+			return last, fmt.Errorf("%w: line %d doesn't match its hash", ErrAuditTampered, line)
+		case rec.Prev != last.Hash || rec.Seq != last.Seq+1:
+			return last, fmt.Errorf("%w: line %d doesn't follow record %d", ErrAuditTampered, line, last.Seq)
+		}
+		last = rec
+	}
+	return last, scanner.Err()
+}
+
+// WithAudit records every secret the generator produces in a new [AuditLog]
+// writing to w. Secrets that a batch discards as duplicates are recorded
+// too. If a record can't be written, generation fails with an error matching
+// [ErrSinkUnavailable].
+//
+// Records include an unsalted SHA-256 fingerprint of each secret, which lets
+// anyone holding a secret show that it appears in the log, but also lets
+// anyone holding the log confirm guesses of low-entropy secrets. Keep the log
+// as confidential as the entropy of the secrets requires.
+func WithAudit(w io.Writer) Option {
+	return WithAuditLog(NewAuditLog(w))
+}
+
+// WithAuditLog is like [WithAudit], but appends records to an existing audit
+// log, such as one opened with [OpenAuditLog] or shared with other
+// generators.
+func WithAuditLog(l *AuditLog) Option {
+	return func(g *Generator) {
+		g.audit = l
+	}
+}
+
+// WithPolicyName records the name of the policy the generator's configuration
+// implements in audit logs. [Policy.Options] includes it.
+func WithPolicyName(name string) Option {
+	return func(g *Generator) {
+		g.policy = name
+	}
+}
+
+// record adds a generated secret to the generator's audit log, if it has one.
+func (g *Generator) record(s string) error {
+	if g.audit == nil {
+		return nil
+	}
+	r := AuditRecord{
+		Policy:     g.policy,
+		Passphrase: g.Passphrase(),
+		Entropy:    g.Entropy(),
+	}
+	sum := sha256.Sum256([]byte(s))
+	r.Fingerprint = hex.EncodeToString(sum[:])
+	if r.Passphrase {
+		r.Length, r.Alphabet = g.words, len(g.wordlist)
+	} else {
+		r.Length = g.passwordLength()
+		if g.positions == nil {
+			r.Alphabet = len(g.charset)
+		}
+		for _, c := range g.required {
+			r.Required = append(r.Required, c.String())
+		}
+	}
+	return g.audit.Record(r)
+}
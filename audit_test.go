@@ -0,0 +1,79 @@
+package genpass
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// auditLines returns the lines of an audit log of n records.
+func auditLines(t *testing.T, n int) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	l := NewAuditLog(&buf)
+	for i := range n {
+		if err := l.Record(AuditRecord{Length: 16 + i, Alphabet: 94, Entropy: 104.9, Fingerprint: "ab"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	lines := auditLines(t, 4)
+	n, err := VerifyAuditLog(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil || n != 4 {
+		t.Fatalf("VerifyAuditLog = %d, %v, want 4, nil", n, err)
+	}
+
+	tampered := map[string][]string{
+		"modified length":      slices.Concat(lines[:1], []string{strings.Replace(lines[1], `"length":17`, `"length":8`, 1)}, lines[2:]),
+		"modified fingerprint": slices.Concat(lines[:1], []string{strings.Replace(lines[1], `"fingerprint":"ab"`, `"fingerprint":"cd"`, 1)}, lines[2:]),
+		"reordered":            {lines[0], lines[2], lines[1], lines[3]},
+		"deleted":              slices.Concat(lines[:1], lines[2:]),
+		"first deleted":        lines[1:],
+	}
+	for name, lines := range tampered {
+		if _, err := VerifyAuditLog(strings.NewReader(strings.Join(lines, "\n"))); !errors.Is(err, ErrAuditTampered) {
+			t.Errorf("%s: VerifyAuditLog = %v, want %v", name, err, ErrAuditTampered)
+		}
+	}
+}
+
+func TestOpenAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for range 2 {
+		l, f, err := OpenAuditLog(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 2 {
+			if err := l.Record(AuditRecord{Length: 16}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		f.Close()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := VerifyAuditLog(bytes.NewReader(data)); err != nil || n != 4 {
+		t.Fatalf("VerifyAuditLog = %d, %v, want 4, nil", n, err)
+	}
+
+	// a log that has been tampered with is not continued
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	lines[0], lines[1] = lines[1], lines[0]
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := OpenAuditLog(path); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("OpenAuditLog of a tampered log = %v, want %v", err, ErrAuditTampered)
+	}
+}
@@ -0,0 +1,242 @@
+package genpass
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSyncEvery is the default number of secrets written between syncs in
+// [WriteBatch].
+const DefaultSyncEvery = 10000
+
+// Checkpoint records the progress of a batch written by [WriteBatch].
+type Checkpoint struct {
+	// Written is the number of secrets written.
+	Written int `json:"written"`
+	// Offset is the number of bytes written to the output.
+	Offset int64 `json:"offset"`
+}
+
+// LoadCheckpoint reads a checkpoint file. If the file doesn't exist, it
+// returns a zero checkpoint.
+func LoadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, err
+	}
+	err = json.Unmarshal(data, &cp)
+	return cp, err
+}
+
+func (cp Checkpoint) save(path string) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// BatchConfig configures [WriteBatch].
+type BatchConfig struct {
+	// Count is the total number of secrets in the batch.
+	Count int
+	// SyncEvery is the number of secrets written between syncs. At each sync,
+	// buffered output is flushed, the writer is synced to stable storage if
+	// it is a regular [*os.File] or has a Sync method, and the checkpoint is
+	// updated.
+	// If zero, [DefaultSyncEvery] is used.
+	SyncEvery int
+	// Checkpoint is the path of the checkpoint file. If empty, no checkpoint
+	// is kept. The file is removed when the batch completes.
+	Checkpoint string
+	// Resume is the progress to resume from, usually loaded with
+	// [LoadCheckpoint]. The writer must be positioned at Resume.Offset.
+	Resume Checkpoint
+	// Unique, if set, makes the batch free of duplicates. Each secret is added
+	// to the filter, and secrets that are possibly present already are
+	// discarded and generated again. When resuming, the filter must contain
+	// the secrets written before Resume.
+	Unique *BloomFilter
+	// OnSync, if set, is called after each sync with the progress that is now
+	// durable. It can be used to persist additional state, like Unique.
+	OnSync func(Checkpoint) error
+	// Format, if set, is applied to each secret before it is written, for
+	// example to append a checksum. Unique sees secrets before formatting.
+	Format func(string) string
+}
+
+// WriteBatch writes secrets from gen to w, one per line, until cfg.Count
+// secrets have been written in total. Memory use is bounded regardless of the
+// batch size: each secret is written as soon as it is generated, and a slow
+// writer slows generation down rather than causing output to accumulate.
+//
+// It returns the progress made, which can be passed back as cfg.Resume to
+// continue after an error.
+func WriteBatch(w io.Writer, gen *Generator, cfg BatchConfig) (Checkpoint, error) {
+	if err := gen.Validate(); err != nil {
+		return cfg.Resume, err
+	}
+	syncEvery := cfg.SyncEvery
+	if syncEvery <= 0 {
+		syncEvery = DefaultSyncEvery
+	}
+
+	bw := bufio.NewWriter(w)
+	durable := cfg.Resume
+	progress := cfg.Resume
+
+	sync := func() (err error) {
+		count := progress.Written - durable.Written
+		defer func() {
+			notify(func(o Observer) { o.OnSinkWrite(SinkWriteEvent{Sink: "batch", Count: count, Err: err}) })
+		}()
+		if err := bw.Flush(); err != nil {
+			return &SinkError{Sink: "batch", Err: err}
+		}
+		if err := syncWriter(w); err != nil {
+			return &SinkError{Sink: "batch", Err: err}
+		}
+		if cfg.Checkpoint != "" {
+			if err := progress.save(cfg.Checkpoint); err != nil {
+				return &SinkError{Sink: "batch", Err: err}
+			}
+		}
+		durable = progress
+		if cfg.OnSync != nil {
+			return cfg.OnSync(durable)
+		}
+		return nil
+	}
+
+	rejected := 0
+	for progress.Written < cfg.Count {
+		secret, err := gen.Generate()
+		if err != nil {
+			return durable, err
+		}
+		if cfg.Unique != nil && cfg.Unique.Add(secret) {
+			notify(func(o Observer) { o.OnReject(RejectEvent{Reason: RejectDuplicate}) })
+			if rejected++; rejected >= maxAttempts {
+				return durable, fmt.Errorf("%w: no unique secret found after %d attempts", ErrKeyspaceExhausted, maxAttempts)
+			}
+			continue
+		}
+		rejected = 0
+		if cfg.Format != nil {
+			secret = cfg.Format(secret)
+		}
+		n, err := bw.WriteString(secret + "\n")
+		if err != nil {
+			return durable, &SinkError{Sink: "batch", Err: err}
+		}
+		progress.Written++
+		progress.Offset += int64(n)
+
+		if progress.Written%syncEvery == 0 {
+			if err := sync(); err != nil {
+				return durable, err
+			}
+		}
+	}
+
+	if err := sync(); err != nil {
+		return durable, err
+	}
+	if cfg.Checkpoint != "" {
+		if err := os.Remove(cfg.Checkpoint); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return durable, err
+		}
+	}
+	return durable, nil
+}
+
+// syncWriter syncs w to stable storage. Files other than regular files, like
+// pipes and terminals, can't be synced and are left alone.
+func syncWriter(w io.Writer) error {
+	if f, ok := w.(*os.File); ok {
+		fi, err := f.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return nil
+		}
+		return f.Sync()
+	}
+	if s, ok := w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// GenerateBatch generates n secrets from cfg in parallel, using one worker per
+// available CPU (GOMAXPROCS), each with its own buffered entropy reader. The
+// configuration is validated once up front rather than for every secret.
+//
+// If ctx is canceled before the batch is complete, GenerateBatch stops and
+// returns ctx.Err().
+func GenerateBatch(ctx context.Context, cfg Config, n int) ([]string, error) {
+	gen, err := cfg.Generator()
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	out := make([]string, n)
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	workers := min(runtime.GOMAXPROCS(0), n)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entropy := newEntropyReader(entropyBatchSize)
+			for {
+				// claim secrets in chunks to keep contention on next low
+				start := int(next.Add(batchChunk)) - batchChunk
+				if start >= n || ctx.Err() != nil {
+					return
+				}
+				for i := start; i < min(start+batchChunk, n); i++ {
+					s, err := gen.generate(entropy)
+					if err != nil {
+						cancel(err)
+						return
+					}
+					out[i] = s
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// batchChunk is the number of secrets a [GenerateBatch] worker claims at a
+// time.
+const batchChunk = 256
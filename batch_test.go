@@ -0,0 +1,37 @@
+package genpass
+
+import (
+	"context"
+	"testing"
+)
+
+// benchBatchConfig is the configuration and size of the batches generated by
+// the batch benchmarks.
+var benchBatchConfig = Config{Charset: "all", Length: 16, Require: []string{"lower", "upper", "digit"}}
+
+const benchBatchSize = 10000
+
+func BenchmarkGenerateBatch(b *testing.B) {
+	for b.Loop() {
+		if _, err := GenerateBatch(context.Background(), benchBatchConfig, benchBatchSize); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGenerateLoop generates the same batch as BenchmarkGenerateBatch
+// with a loop over [Generator.Generate], for comparison.
+func BenchmarkGenerateLoop(b *testing.B) {
+	gen, err := benchBatchConfig.Generator()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		out := make([]string, benchBatchSize)
+		for i := range out {
+			if out[i], err = gen.Generate(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build js && wasm
+
+// Command genpass-wasm exposes the genpass generator to JavaScript, so web
+// pages can generate passwords with exactly the same logic as Go services.
+// Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o genpass.wasm ./bin/genpass-wasm
+//
+// and load it with the wasm_exec.js shipped with Go (in
+// $(go env GOROOT)/lib/wasm). Once running, it defines a global genpass object
+// with two functions, each taking either a charset and length or a config:
+//
+//	genpass.generate(charset, length) // a password, e.g. ("alpha+num", 20)
+//	genpass.generate(config)          // a password from a genpass.Config object
+//	genpass.entropy(charset, length)  // entropy in bits of the same arguments
+//	genpass.entropy(config)
+//
+// Charsets are expressions understood by genpass.ParseCharset. Invalid
+// arguments make the functions return an Error instead of a string or number,
+// since Go functions can't throw JavaScript exceptions.
+//
+// Randomness comes from crypto/rand, which on js/wasm reads from the
+// browser's crypto.getRandomValues.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall/js"
+
+	"github.com/calico32/genpass"
+)
+
+func main() {
+	js.Global().Set("genpass", map[string]any{
+		"generate": js.FuncOf(generate),
+		"entropy":  js.FuncOf(entropy),
+	})
+	// keep the functions alive for the lifetime of the page
+	select {}
+}
+
+// generate implements genpass.generate.
+func generate(this js.Value, args []js.Value) any {
+	g, err := generator(args)
+	if err != nil {
+		return jsError(err)
+	}
+	password, err := g.Generate()
+	if err != nil {
+		return jsError(err)
+	}
+	return password
+}
+
+// entropy implements genpass.entropy.
+func entropy(this js.Value, args []js.Value) any {
+	g, err := generator(args)
+	if err != nil {
+		return jsError(err)
+	}
+	return g.Entropy()
+}
+
+// generator creates a generator from either a charset and length or a config
+// object.
+func generator(args []js.Value) (*genpass.Generator, error) {
+	var config genpass.Config
+	switch {
+	case len(args) == 1 && args[0].Type() == js.TypeObject:
+		s := js.Global().Get("JSON").Call("stringify", args[0]).String()
+		if err := json.Unmarshal([]byte(s), &config); err != nil {
+			return nil, err
+		}
+	case len(args) == 2 && args[0].Type() == js.TypeString && args[1].Type() == js.TypeNumber:
+		config.Charset = args[0].String()
+		config.Length = args[1].Int()
+	default:
+		return nil, errors.New("genpass: expected (charset, length) or (config)")
+	}
+	return config.Generator()
+}
+
+// jsError converts err to a JavaScript Error.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
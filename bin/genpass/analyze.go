@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdAnalyze prints the hashcat mask and brute-force keyspace of passwords
+// given as arguments or read from standard input, one per line.
+//
+//	genpass analyze [-rate 1e10] [-mask] [PASSWORD...]
+func cmdAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	rate := fs.Float64("rate", 1e10, "guesses per second to estimate cracking time with")
+	masksOnly := fs.Bool("mask", false, "print only the masks, as an hcmask file")
+	fs.Parse(args)
+
+	passwords := fs.Args()
+	if len(passwords) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			passwords = append(passwords, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	if *masksOnly {
+		for _, p := range passwords {
+			fmt.Println(genpass.Analyze(p).Mask)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MASK\tKEYSPACE\tBITS\tTIME\tBRUTE FORCE (?a)\tBITS")
+	for _, p := range passwords {
+		a := genpass.Analyze(p)
+		fmt.Fprintf(w, "%s\t%s\t%.1f\t%s\t%s\t%.1f\n",
+			a.Mask, formatKeyspace(a.Keyspace), a.Bits, crackTime(a.Keyspace, *rate),
+			formatKeyspace(a.BruteForceKeyspace), a.BruteForceBits)
+	}
+	return w.Flush()
+}
+
+// formatKeyspace formats a number of candidates, switching to scientific
+// notation once it gets too long to read.
+func formatKeyspace(n *big.Int) string {
+	if n.BitLen() < 50 {
+		return n.String()
+	}
+	return new(big.Float).SetInt(n).Text('e', 2)
+}
+
+// crackTime formats the time needed to exhaust a keyspace at rate guesses per
+// second.
+func crackTime(keyspace *big.Int, rate float64) string {
+	seconds, _ := new(big.Float).Quo(new(big.Float).SetInt(keyspace), big.NewFloat(rate)).Int(nil)
+	return genpass.FormatDuration(seconds)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/calico32/genpass"
+)
+
+var flagCount = flag.String("count", "", "generate this many secrets, one per line (e.g. 1000 or 10e6)")
+var flagOutput = flag.String("output", "", "write batch output to this file instead of stdout, or emit the secret as k8s-secret or vault-kv (use ./name for a file with that name)")
+var flagCheckpoint = flag.String("checkpoint", "", "record batch progress in this file and resume from it (requires --output)")
+var flagSyncEvery = flag.Int("sync-every", genpass.DefaultSyncEvery, "flush and sync batch output every this many secrets")
+
+// batchCount returns the number of secrets requested with --count, or 0 if
+// --count wasn't given.
+func batchCount() (int, error) {
+	if *flagCount == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(*flagCount, 64)
+	if err != nil || f < 1 || f != float64(int(f)) {
+		return 0, usagef("invalid count %q", *flagCount)
+	}
+	return int(f), nil
+}
+
+// runBatch writes count secrets from gen to --output or stdout.
+func runBatch(gen *genpass.Generator, count int) error {
+	cfg := genpass.BatchConfig{
+		Count:      count,
+		SyncEvery:  *flagSyncEvery,
+		Checkpoint: *flagCheckpoint,
+		Format:     formatBatchSecret,
+	}
+	var written int64
+	write := func() (err error) {
+		written, err = writeBatch(gen, cfg)
+		return err
+	}
+	if *flagStats {
+		stats, stop := startStats()
+		defer func() {
+			stop()
+			stats.bytes = written
+			stats.print(os.Stderr)
+		}()
+	}
+	if *flagOutput != "" && *flagCheckpoint == "" {
+		// batches with a checkpoint continue the previous file rather than
+		// replacing it
+		return replaceFile(*flagOutput, write)
+	}
+	return write()
+}
+
+// formatBatchSecret applies the formatting printSecret applies to single
+// secrets that also makes sense for batches.
+func formatBatchSecret(secret string) string {
+	// like --raw, batches hold the secrets themselves rather than a display
+	if *flagGroup > 0 && !*flagGroupDisplay {
+		secret = genpass.Group(secret, *flagGroup, *flagSeparator)
+	}
+	if *flagTranscriptionCheck {
+		secret = genpass.AppendChecksum(secret)
+	}
+	return secret
+}
+
+// writeBatch writes the batch described by cfg to --output or stdout and
+// returns the number of bytes written.
+func writeBatch(gen *genpass.Generator, cfg genpass.BatchConfig) (int64, error) {
+	var w io.Writer = os.Stdout
+	if *flagOutput != "" {
+		f, err := os.OpenFile(*flagOutput, os.O_RDWR|os.O_CREATE, 0o600)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+
+		if *flagCheckpoint != "" {
+			cfg.Resume, err = genpass.LoadCheckpoint(*flagCheckpoint)
+			if err != nil {
+				return 0, err
+			}
+			if cfg.Resume.Written > 0 {
+				fmt.Fprintf(os.Stderr, "resuming after %d secrets\n", cfg.Resume.Written)
+			}
+		}
+		// discard anything written after the last checkpoint
+		if err := f.Truncate(cfg.Resume.Offset); err != nil {
+			return 0, err
+		}
+		if _, err := f.Seek(cfg.Resume.Offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+		w = f
+	} else if *flagCheckpoint != "" {
+		return 0, usageError("--checkpoint requires --output")
+	}
+
+	progress, err := genpass.WriteBatch(w, gen, cfg)
+	return progress.Offset - cfg.Resume.Offset, err
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/calico32/genpass"
+)
+
+// Read sizes used to benchmark entropy sources, matching the batches genpass
+// reads for large outputs and for short passwords.
+const (
+	benchThroughputSize = 64 * 1024
+	benchLatencySize    = 512
+)
+
+// rngBenchmark is the result of benchmarking an entropy source.
+type rngBenchmark struct {
+	source     genpass.EntropySource
+	err        error
+	throughput float64 // bytes per second
+	p50, p99   time.Duration
+}
+
+// cmdBenchRNG measures the throughput and latency of the available entropy
+// sources, and optionally saves the fastest one in the config file.
+//
+//	genpass bench-rng [-duration 1s] [-write]
+func cmdBenchRNG(args []string) error {
+	fs := flag.NewFlagSet("bench-rng", flag.ExitOnError)
+	duration := fs.Duration("duration", time.Second, "time spent benchmarking each source")
+	write := fs.Bool("write", false, "save the fastest source in the config file")
+	fs.Parse(args)
+
+	var results []rngBenchmark
+	for _, name := range genpass.EntropySources() {
+		src, _ := genpass.LookupEntropySource(name)
+		results = append(results, benchRNG(src, *duration))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tTHROUGHPUT\tLATENCY P50\tLATENCY P99\tDESCRIPTION")
+	var best *rngBenchmark
+	for i, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "%s\tunavailable: %v\t\t\t%s\n", r.source.Name, r.err, r.source.Description)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%v\t%v\t%s\n", r.source.Name, formatThroughput(r.throughput), r.p50, r.p99, r.source.Description)
+		if best == nil || r.throughput > best.throughput {
+			best = &results[i]
+		}
+	}
+	w.Flush()
+
+	if best == nil {
+		return fmt.Errorf("%w: no entropy source is available", genpass.ErrEntropySource)
+	}
+	fmt.Printf("\nfastest: %s (current: %s)\n", best.source.Name, genpass.CurrentEntropySource())
+	if !*write {
+		return nil
+	}
+
+	c, path, err := readConfig()
+	if err != nil {
+		return err
+	}
+	c.EntropySource = best.source.Name
+	if err := writeConfig(path, c); err != nil {
+		return err
+	}
+	fmt.Printf("saved entropy-source: %s to %s\n", best.source.Name, path)
+	return nil
+}
+
+// benchRNG reads from src for d, half of it in large reads to measure
+// throughput and half in small reads to measure latency.
+func benchRNG(src genpass.EntropySource, d time.Duration) rngBenchmark {
+	b := rngBenchmark{source: src}
+	r, err := src.Open()
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	buf := make([]byte, benchThroughputSize)
+	total := 0
+	start := time.Now()
+	for total == 0 || time.Since(start) < d/2 {
+		n, err := io.ReadFull(r, buf)
+		total += n
+		if err != nil {
+			b.err = err
+			return b
+		}
+	}
+	b.throughput = float64(total) / time.Since(start).Seconds()
+
+	var latencies []time.Duration
+	start = time.Now()
+	for len(latencies) == 0 || time.Since(start) < d/2 {
+		t := time.Now()
+		if _, err := io.ReadFull(r, buf[:benchLatencySize]); err != nil {
+			b.err = err
+			return b
+		}
+		latencies = append(latencies, time.Since(t))
+	}
+	slices.Sort(latencies)
+	b.p50 = latencies[len(latencies)/2]
+	b.p99 = latencies[len(latencies)*99/100]
+	return b
+}
+
+// formatThroughput formats a rate in bytes per second.
+func formatThroughput(rate float64) string {
+	if rate < 1e6 {
+		return fmt.Sprintf("%.1f kB/s", rate/1e3)
+	}
+	return fmt.Sprintf("%.1f MB/s", rate/1e6)
+}
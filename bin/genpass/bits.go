@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+var flagBits = flag.Float64("bits", 0, "use the shortest length that provides this many bits of entropy")
+
+// maxBitsLength bounds the search of lengthForBits.
+const maxBitsLength = 1 << 16
+
+// lengthForBits returns the shortest password or passphrase length for which
+// the configuration in opts provides the entropy given with --bits. The first
+// guess assumes uniform choices from the charset or wordlist; it is adjusted
+// for anything that changes the entropy per character or word, like required
+// classes, weights, and transforms.
+func lengthForBits(opts []genpass.Option, charset string, wordlist []string) int {
+	alphabet := len([]rune(charset))
+	if *flagPassphrase {
+		alphabet = len(wordlist)
+	}
+	entropy := func(n int) float64 {
+		return genpass.NewGenerator(append(opts, lengthOption(n, wordlist))...).Entropy()
+	}
+
+	n := max(genpass.LengthForEntropy(alphabet, *flagBits), 1)
+	for n > 1 && entropy(n-1) >= *flagBits {
+		n--
+	}
+	for entropy(n) < *flagBits {
+		if n++; n > maxBitsLength {
+			fatal(fmt.Errorf("no length up to %d provides %g bits of entropy", maxBitsLength, *flagBits))
+		}
+	}
+	if !*flagQuiet && !*flagRaw {
+		fmt.Fprintf(os.Stderr, "length: %d (%.2f bits)\n", n, entropy(n))
+	}
+	return n
+}
+
+// lengthOption returns the option that sets the length of passwords, or the
+// number of words of passphrases.
+func lengthOption(n int, wordlist []string) genpass.Option {
+	if *flagPassphrase {
+		return genpass.WithWords(wordlist, n)
+	}
+	return genpass.WithLength(n)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+// commands are the subcommands of genpass, selected by the first argument.
+var commands = map[string]func(args []string) error{
+	"analyze":      cmdAnalyze,
+	"apply":        cmdApply,
+	"audit-list":   cmdAuditList,
+	"audit-verify": cmdAuditVerify,
+	"bench-rng":    cmdBenchRNG,
+	"combine":      cmdCombine,
+	"pgpwords":     cmdPGPWords,
+	"plan":         cmdPlan,
+	"qr-decode":    cmdQRDecode,
+	"rotate":       cmdRotate,
+	"rs-decode":    cmdRSDecode,
+	"selftest":     cmdSelftest,
+	"serve":        cmdServe,
+	"shred":        cmdShred,
+	"key":          cmdKey,
+	"lint-secrets": cmdLintSecrets,
+	"daemon":       cmdDaemon,
+	"fetch":        cmdFetch,
+	"gen":          cmdGen,
+	"get":          cmdGet,
+	"job":          cmdJob,
+	"mnemonic":     cmdMnemonic,
+	"recovery":     cmdRecovery,
+	"validate":     cmdValidate,
+	"verify-code":  cmdVerifyCode,
+	"vectors":      cmdVectors,
+}
+
+// runCommand runs the subcommand named by the first argument, if any, and
+// reports whether one was found.
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return false
+	}
+	if err := cmd(args[1:]); err != nil {
+		fatal(err)
+	}
+	return true
+}
+
+// cmdPGPWords converts a hex string (e.g. a key fingerprint) to PGP words, or
+// PGP words back to hex.
+//
+//	genpass pgpwords E582 94F2 E9A2 2748
+//	genpass pgpwords topmost Istanbul Pluto vagabond
+func cmdPGPWords(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: genpass pgpwords <hex>|<words...>")
+	}
+
+	input := strings.Join(args, " ")
+	cleaned := strings.NewReplacer(" ", "", ":", "", "-", "").Replace(input)
+	if b, err := hex.DecodeString(cleaned); err == nil {
+		fmt.Println(genpass.PGPWords.Encode(b))
+		return nil
+	}
+
+	b, err := genpass.PGPWords.Decode(input)
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.ToUpper(hex.EncodeToString(b)))
+	return nil
+}
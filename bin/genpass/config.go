@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/calico32/genpass"
+	"gopkg.in/yaml.v3"
+)
+
+// config holds settings that apply to every run of genpass, stored in the
+// user's configuration directory.
+type config struct {
+	// EntropySource is the name of the entropy source to generate secrets
+	// from, usually chosen with "genpass bench-rng -write".
+	EntropySource string `yaml:"entropy-source,omitempty"`
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "genpass", "config.yaml"), nil
+}
+
+// readConfig reads the config file, returning an empty config if there is
+// none.
+func readConfig() (config, string, error) {
+	var c config
+	path, err := configPath()
+	if err != nil {
+		return c, "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, path, nil
+	}
+	if err != nil {
+		return c, path, err
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, path, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, path, nil
+}
+
+func writeConfig(path string, c config) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// loadConfig applies the settings in the config file.
+func loadConfig() {
+	c, path, err := readConfig()
+	if err != nil {
+		// without a config directory, there are no settings to apply
+		if path == "" {
+			return
+		}
+		fatal(err)
+	}
+	if c.EntropySource != "" {
+		if err := genpass.UseEntropySource(c.EntropySource); err != nil {
+			fatal(fmt.Errorf("%s: %w", path, err))
+		}
+	}
+}
@@ -0,0 +1,343 @@
+//go:build unix
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	iofs "io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/calico32/genpass"
+
+	"golang.org/x/sys/unix"
+)
+
+// defaultProfiles are available to the daemon without a profiles file.
+var defaultProfiles = map[string]genpass.Config{
+	"default":    {},
+	"passphrase": {Words: 6},
+	"hex":        {Charset: "hex", Length: 32},
+	"token":      {Charset: "alnum", Length: 32},
+}
+
+// slotSize is the maximum size of a cached secret, in bytes.
+const slotSize = 256
+
+func defaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "genpass.sock")
+	}
+	return filepath.Join(fallbackSocketDir(), "genpass.sock")
+}
+
+// fallbackSocketDir is the directory of the default socket without
+// $XDG_RUNTIME_DIR. The temporary directory is shared with other users, who
+// could create a socket at a predictable path in it first, so the socket is
+// put in a directory only the user can access.
+func fallbackSocketDir() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("genpass-%d", os.Getuid()))
+}
+
+// makePrivateDir creates dir with access only for the user if it doesn't
+// exist, and checks that an existing dir belongs to the user and is not
+// accessible to others.
+func makePrivateDir(dir string) error {
+	if err := os.Mkdir(dir, 0o700); err != nil && !errors.Is(err, iofs.ErrExist) {
+		return err
+	}
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !fi.IsDir() || !ok || int(st.Uid) != os.Getuid() || fi.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("%s is not a private directory of the current user", dir)
+	}
+	return nil
+}
+
+// checkSocketOwner checks that the file at path is a socket created by the
+// current user, so that another user can't pose as the daemon.
+func checkSocketOwner(path string) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("cannot connect to daemon: %w", err)
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if fi.Mode().Type() != iofs.ModeSocket || !ok || int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("%s is not a socket owned by the current user", path)
+	}
+	return nil
+}
+
+// cmdDaemon serves pre-generated secrets over a Unix socket.
+//
+//	genpass daemon [-socket path] [-profiles file.json] [-cache n]
+func cmdDaemon(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	socket := fs.String("socket", defaultSocketPath(), "path of the Unix socket to listen on")
+	profilesPath := fs.String("profiles", "", "JSON file mapping profile names to generator configurations")
+	size := fs.Int("cache", 64, "number of secrets to keep ready per profile")
+	fs.Parse(args)
+
+	if *size <= 0 {
+		return usageError("cache size must be positive")
+	}
+
+	profiles := defaultProfiles
+	if *profilesPath != "" {
+		data, err := os.ReadFile(*profilesPath)
+		if err != nil {
+			return err
+		}
+		profiles = map[string]genpass.Config{}
+		if err := json.Unmarshal(data, &profiles); err != nil {
+			return fmt.Errorf("invalid profiles file: %w", err)
+		}
+	}
+
+	caches := map[string]*secretCache{}
+	for name, cfg := range profiles {
+		gen, err := cfg.Generator()
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+		c, err := newSecretCache(gen, *size)
+		if err != nil {
+			return err
+		}
+		caches[name] = c
+	}
+	defer func() {
+		for _, c := range caches {
+			c.close()
+		}
+	}()
+
+	if filepath.Dir(*socket) == fallbackSocketDir() {
+		if err := makePrivateDir(fallbackSocketDir()); err != nil {
+			return err
+		}
+	}
+	os.Remove(*socket)
+	// create the socket without access for others from the start, rather
+	// than restricting it after it already accepts connections
+	umask := syscall.Umask(0o077)
+	l, err := net.Listen("unix", *socket)
+	syscall.Umask(umask)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(*socket)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		l.Close()
+	}()
+
+	names := make([]string, 0, len(caches))
+	for name := range caches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	fmt.Fprintf(os.Stderr, "genpass daemon listening on %s (profiles: %s)\n", *socket, strings.Join(names, ", "))
+
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go serveDaemonConn(conn, caches)
+	}
+}
+
+// serveDaemonConn answers "GET <profile>" requests, one per line, with
+// "OK <secret>" or "ERR <message>".
+func serveDaemonConn(conn net.Conn, caches map[string]*secretCache) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		name, ok := strings.CutPrefix(scanner.Text(), "GET ")
+		if !ok {
+			fmt.Fprintf(conn, "ERR unknown request\n")
+			continue
+		}
+		c, ok := caches[name]
+		if !ok {
+			fmt.Fprintf(conn, "ERR unknown profile %q\n", name)
+			continue
+		}
+		secret, err := c.take()
+		if err != nil {
+			fmt.Fprintf(conn, "ERR %v\n", err)
+			continue
+		}
+		conn.Write([]byte("OK "))
+		conn.Write(secret)
+		conn.Write([]byte("\n"))
+		clear(secret)
+	}
+}
+
+// cmdFetch is the thin client for the daemon.
+//
+//	genpass fetch [-socket path] [profile]
+func cmdFetch(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	socket := fs.String("socket", defaultSocketPath(), "path of the daemon's Unix socket")
+	fs.Parse(args)
+
+	profile := "default"
+	if fs.NArg() > 0 {
+		profile = fs.Arg(0)
+	}
+
+	if err := checkSocketOwner(*socket); err != nil {
+		return err
+	}
+	conn, err := net.Dial("unix", *socket)
+	if err != nil {
+		return fmt.Errorf("cannot connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "GET %s\n", profile)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	if msg, ok := strings.CutPrefix(line, "ERR "); ok {
+		return errors.New(msg)
+	}
+	secret, ok := strings.CutPrefix(line, "OK ")
+	if !ok {
+		return fmt.Errorf("unexpected response from daemon")
+	}
+	fmt.Println(secret)
+	return nil
+}
+
+// secretCache holds pre-generated secrets for one profile in memory that is
+// locked into RAM, so the cache itself is never written to swap. Secrets
+// still pass through ordinary memory while they are generated and sent.
+type secretCache struct {
+	gen    *genpass.Generator
+	mem    []byte
+	mu     sync.Mutex
+	lens   []int // length of the secret in each slot; 0 if empty
+	closed bool
+	fill   chan struct{}
+	done   chan struct{}
+}
+
+func newSecretCache(gen *genpass.Generator, size int) (*secretCache, error) {
+	mem, err := unix.Mmap(-1, 0, size*slotSize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Mlock(mem); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: cannot lock cache memory: %v\n", err)
+	}
+
+	c := &secretCache{
+		gen:  gen,
+		mem:  mem,
+		lens: make([]int, size),
+		fill: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go c.refill()
+	c.fill <- struct{}{}
+	return c, nil
+}
+
+// refill generates secrets into empty slots whenever it is signaled.
+func (c *secretCache) refill() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.fill:
+		}
+		for {
+			c.mu.Lock()
+			slot := slices.Index(c.lens, 0)
+			c.mu.Unlock()
+			if slot < 0 {
+				break
+			}
+
+			secret, err := c.gen.Generate()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: cannot fill cache: %v\n", err)
+				break
+			}
+			if len(secret) > slotSize {
+				fmt.Fprintf(os.Stderr, "warning: cannot fill cache: secrets of %d bytes don't fit in the %d-byte slots\n", len(secret), slotSize)
+				break
+			}
+
+			c.mu.Lock()
+			if c.closed {
+				c.mu.Unlock()
+				return
+			}
+			copy(c.mem[slot*slotSize:], secret)
+			c.lens[slot] = len(secret)
+			c.mu.Unlock()
+		}
+	}
+}
+
+// take removes a secret from the cache, generating one on demand if the cache
+// is empty. The caller should clear the returned slice after use.
+func (c *secretCache) take() ([]byte, error) {
+	c.mu.Lock()
+	defer func() {
+		select {
+		case c.fill <- struct{}{}:
+		default:
+		}
+	}()
+
+	slot := slices.IndexFunc(c.lens, func(n int) bool { return n > 0 })
+	if slot < 0 || c.closed {
+		c.mu.Unlock()
+		secret, err := c.gen.Generate()
+		return []byte(secret), err
+	}
+	region := c.mem[slot*slotSize : slot*slotSize+slotSize]
+	secret := make([]byte, c.lens[slot])
+	copy(secret, region)
+	clear(region)
+	c.lens[slot] = 0
+	c.mu.Unlock()
+	return secret, nil
+}
+
+func (c *secretCache) close() {
+	close(c.done)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	clear(c.mem)
+	unix.Munlock(c.mem)
+	unix.Munmap(c.mem)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+import "errors"
+
+func cmdDaemon(args []string) error {
+	return errors.New("the daemon is only supported on Unix systems")
+}
+
+func cmdFetch(args []string) error {
+	return errors.New("the daemon is only supported on Unix systems")
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+var flagDenylist = flag.String("denylist", "", "reject passwords containing any word in this file (one per line, ignoring case and leet-speak)")
+var flagUsername = flag.String("username", "", "reject passwords containing this account name")
+
+// denylistOption returns the generator option for --denylist and --username,
+// or nil if neither is set.
+func denylistOption() (genpass.Option, error) {
+	var words []string
+	if *flagDenylist != "" {
+		f, err := os.Open(*flagDenylist)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				words = append(words, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if *flagUsername != "" {
+		words = append(words, *flagUsername)
+	}
+	if words == nil {
+		return nil, nil
+	}
+	return genpass.WithDenylist(words), nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/calico32/genpass"
+)
+
+var flagShowDerivation = flag.Bool("show-entropy-derivation", false, "show how the entropy is computed from each component of the configuration")
+
+// printDerivation prints the terms of an entropy derivation and their total,
+// if --show-entropy-derivation was given.
+func printDerivation(terms []genpass.EntropyTerm) {
+	if !*flagShowDerivation {
+		return
+	}
+	total := 0.0
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Println("Entropy derivation:")
+	for _, t := range terms {
+		fmt.Fprintf(w, "  %s\t%s\t%+8.2f bits\n", t.Source, t.Formula, t.Bits)
+		total += t.Bits
+	}
+	fmt.Fprintf(w, "  total\t\t%8.2f bits\n", total)
+	w.Flush()
+}
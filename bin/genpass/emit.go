@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+var flagName = flag.String("name", "", "name of the emitted secret (with --output k8s-secret)")
+var flagKey = flag.String("key", "password", "key of the emitted secret (with --output k8s-secret or vault-kv)")
+
+// outputEmitter returns the emitter selected with --output, if its value
+// names one rather than a file.
+func outputEmitter() (genpass.Emitter, bool) {
+	if *flagOutput == "" {
+		return nil, false
+	}
+	return genpass.LookupEmitter(*flagOutput)
+}
+
+// emitSecret writes secret to stdout in the format selected with --output.
+func emitSecret(e genpass.Emitter, secret string) {
+	err := e.Emit(os.Stdout, genpass.NamedSecret{Name: *flagName, Key: *flagKey, Value: secret})
+	if err != nil {
+		fatal(err)
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+var flagEncryptTo = flag.String("encrypt-to", "", "print the secret only as an armored age file for these recipients (comma-separated age or SSH public keys)")
+
+// printEncrypted prints secret encrypted to the recipients given with
+// --encrypt-to.
+func printEncrypted(secret string) {
+	armored, err := genpass.EncryptFor(strings.Split(*flagEncryptTo, ","), []byte(secret))
+	if err != nil {
+		fatal(err)
+	}
+	os.Stdout.Write(armored)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+var flagErrorFormat = flag.String("error-format", "text", "print errors as text or json (before a command name to apply to the command)")
+
+// Exit codes of genpass, so that scripts can tell failures apart. Invalid
+// flags to subcommands also exit with 2, like other programs using the flag
+// package.
+const (
+	exitError    = 1 // any other error
+	exitUsage    = 2 // usageError
+	exitPolicy   = 3 // genpass.ErrPolicyViolation
+	exitEntropy  = 4 // genpass.ErrEntropySource
+	exitSink     = 5 // genpass.ErrSinkUnavailable
+	exitKeyspace = 6 // genpass.ErrKeyspaceExhausted
+	exitWeak     = 7 // *genpass.EntropyError, from --min-entropy
+)
+
+// usageError is an error in how genpass was invoked, like a missing argument
+// or conflicting flags.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// usagef returns a usageError with a formatted message.
+func usagef(format string, args ...any) error {
+	return usageError(fmt.Sprintf(format, args...))
+}
+
+// exitCode returns the exit code for err.
+func exitCode(err error) int {
+	var usage usageError
+	var weak *genpass.EntropyError
+	switch {
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.As(err, &weak):
+		return exitWeak
+	case errors.Is(err, genpass.ErrPolicyViolation):
+		return exitPolicy
+	case errors.Is(err, genpass.ErrEntropySource):
+		return exitEntropy
+	case errors.Is(err, genpass.ErrSinkUnavailable):
+		return exitSink
+	case errors.Is(err, genpass.ErrKeyspaceExhausted):
+		return exitKeyspace
+	default:
+		return exitError
+	}
+}
+
+// errorCodes are the names of the exit codes in JSON errors.
+var errorCodes = map[int]string{
+	exitError:    "error",
+	exitUsage:    "usage",
+	exitPolicy:   "policy-violation",
+	exitEntropy:  "entropy-source",
+	exitSink:     "sink-unavailable",
+	exitKeyspace: "keyspace-exhausted",
+	exitWeak:     "insufficient-entropy",
+}
+
+// jsonError is an error printed with --error-format json.
+type jsonError struct {
+	Error      string          `json:"error"`
+	Code       string          `json:"code"`
+	ExitCode   int             `json:"exitCode"`
+	Violations []jsonViolation `json:"violations,omitempty"`
+	Sink       string          `json:"sink,omitempty"`
+	// Entropy and MinEntropy are the bits provided and required for
+	// insufficient-entropy errors.
+	Entropy    *float64 `json:"entropy,omitempty"`
+	MinEntropy *float64 `json:"minEntropy,omitempty"`
+}
+
+type jsonViolation struct {
+	Code    genpass.ViolationCode `json:"code"`
+	Message string                `json:"message"`
+}
+
+// errorFormatArgs removes --error-format flags that come before the command
+// name from args and applies them, so that they also apply to subcommands,
+// which are run before the main flags are parsed.
+func errorFormatArgs(args []string) []string {
+	for len(args) > 0 {
+		switch {
+		case args[0] == "--error-format" && len(args) > 1:
+			*flagErrorFormat = args[1]
+			args = args[2:]
+		case strings.HasPrefix(args[0], "--error-format="):
+			*flagErrorFormat = strings.TrimPrefix(args[0], "--error-format=")
+			args = args[1:]
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+// fatal prints err and exits with the matching exit code.
+func fatal(err error) {
+	code := exitCode(err)
+	if *flagErrorFormat != "json" {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(code)
+	}
+
+	out := jsonError{Error: err.Error(), Code: errorCodes[code], ExitCode: code}
+	var policyErr *genpass.PolicyError
+	if errors.As(err, &policyErr) {
+		for _, v := range policyErr.Violations {
+			out.Violations = append(out.Violations, jsonViolation{v.Code, v.Message})
+		}
+	}
+	var sinkErr *genpass.SinkError
+	if errors.As(err, &sinkErr) {
+		out.Sink = sinkErr.Sink
+	}
+	var entropyErr *genpass.EntropyError
+	if errors.As(err, &entropyErr) {
+		out.Entropy, out.MinEntropy = &entropyErr.Entropy, &entropyErr.Min
+	}
+	enc := json.NewEncoder(os.Stderr)
+	enc.SetEscapeHTML(false)
+	enc.Encode(out)
+	os.Exit(code)
+}
+
+// checkErrorFormat exits with a usage error if --error-format is unknown.
+func checkErrorFormat() {
+	if *flagErrorFormat != "text" && *flagErrorFormat != "json" {
+		format := *flagErrorFormat
+		*flagErrorFormat = "text"
+		fatal(usagef("unknown error format %q (available: text, json)", format))
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+// genOutput is the JSON output of cmdGen.
+type genOutput struct {
+	Scheme string `json:"scheme"`
+	genpass.Secret
+	Entropy  float64 `json:"entropy"`
+	Strength string  `json:"strength"`
+}
+
+// cmdGen generates a secret with a registered [genpass.Scheme], including
+// schemes registered by packages imported in plugins.go. Without a name, it
+// lists the available schemes.
+//
+//	genpass gen passphrase -words 5 -capitalize -entropy
+//	genpass gen password -length 20 -policy pci -json
+func cmdGen(args []string) error {
+	if len(args) == 0 {
+		fmt.Printf("schemes: %s\n", strings.Join(genpass.Schemes(), ", "))
+		return nil
+	}
+	if strings.HasPrefix(args[0], "-") {
+		return usageError("usage: genpass gen <scheme> [flags]")
+	}
+	name := args[0]
+	if _, ok := genpass.LookupScheme(name); !ok {
+		return usagef("unknown scheme %q (available: %s)", name, strings.Join(genpass.Schemes(), ", "))
+	}
+
+	var cfg genpass.Config
+	fs := flag.NewFlagSet("gen "+name, flag.ExitOnError)
+	fs.StringVar(&cfg.Charset, "charset", "", "charset expression, e.g. alpha+num")
+	fs.IntVar(&cfg.Length, "length", 0, "number of characters")
+	require := fs.String("require", "", "require at least one character from each class (comma-separated)")
+	fs.IntVar(&cfg.Group, "group", 0, "split the secret into groups of this many characters")
+	fs.StringVar(&cfg.GroupSeparator, "group-separator", "", "separator between groups")
+	fs.IntVar(&cfg.Words, "words", 0, "number of passphrase words")
+	separator := fs.String("separator", " ", "separator between passphrase words")
+	fs.BoolVar(&cfg.Capitalize, "capitalize", false, "capitalize passphrase words")
+	fs.Float64Var(&cfg.Leet, "leet", 0, "probability of each leet-speak replacement in passphrase words")
+	fs.BoolVar(&cfg.AddDigit, "add-digit", false, "append a random digit to a random passphrase word")
+	fs.Float64Var(&cfg.MinEntropy, "min-entropy", 0, "fail if the scheme provides fewer bits of entropy")
+	policyName := fs.String("policy", "", "fail if the secret does not satisfy this policy ("+strings.Join(genpass.Policies(), ", ")+")")
+	showEntropy := fs.Bool("entropy", false, "show entropy")
+	asJSON := fs.Bool("json", false, "print the secret, its fields, and its entropy as JSON")
+	fs.Parse(args[1:])
+	if fs.NArg() > 0 {
+		return usageError("usage: genpass gen <scheme> [flags]")
+	}
+	if *require != "" {
+		cfg.Require = strings.Split(*require, ",")
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "separator" {
+			cfg.Separator = separator
+		}
+	})
+
+	var policy genpass.Policy
+	if *policyName != "" {
+		var ok bool
+		if policy, ok = genpass.LookupPolicy(*policyName); !ok {
+			return usagef("unknown policy %q", *policyName)
+		}
+	}
+
+	secret, err := genpass.GenerateScheme(name, cfg)
+	if err != nil {
+		return err
+	}
+	if *policyName != "" {
+		if err := policy.Check(secret.Value); err != nil {
+			return err
+		}
+	}
+
+	scheme, _ := genpass.LookupScheme(name)
+	e := scheme.Entropy(cfg)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(genOutput{
+			Scheme:   scheme.Name(),
+			Secret:   secret,
+			Entropy:  e,
+			Strength: genpass.StrengthOf(e).String(),
+		})
+	}
+
+	fmt.Println(secret.Value)
+	for _, k := range slices.Sorted(maps.Keys(secret.Fields)) {
+		fmt.Printf("%s: %s\n", k, secret.Fields[k])
+	}
+	if *showEntropy {
+		fmt.Printf("Entropy: %.2f bits (%s)\n", e, genpass.StrengthOf(e))
+	}
+	return nil
+}
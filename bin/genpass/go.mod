@@ -2,4 +2,8 @@ module genpass
 
 go 1.24.1
 
-require rsc.io/getopt v0.0.0-20170811000552-20be20937449
+require (
+	golang.org/x/time v0.9.0
+	gopkg.in/yaml.v3 v3.0.1
+	rsc.io/getopt v0.0.0-20170811000552-20be20937449
+)
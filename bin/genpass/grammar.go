@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+var flagGrammar = flag.String("grammar", "", "generate a memorable secret from parts like adjective-noun-verb-number-symbol")
+
+// generateGrammar prints a secret with the structure given with --grammar.
+func generateGrammar() {
+	g, err := genpass.ParseGrammar(*flagGrammar)
+	if err != nil {
+		fatal(err)
+	}
+	if e := g.Entropy(); e < *flagMinEntropy {
+		fatal(&genpass.EntropyError{Entropy: e, Min: *flagMinEntropy})
+	}
+
+	secret, e := genpass.GenerateFromGrammar(g)
+	if !*flagQuiet && !*flagRaw && e < genpass.RecommendedEntropy {
+		msg := fmt.Sprintf(genpass.CurrentLocale().T(genpass.MsgWarnLowEntropy), e, genpass.RecommendedEntropy)
+		fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+	}
+
+	printSecret(secret)
+	remember(secret, e)
+	if secretOnly() {
+		return
+	}
+	if *flagEntropy {
+		fmt.Printf("Entropy: %.2f bits (%s)\n", e, genpass.StrengthOf(e))
+	}
+	printDerivation(g.EntropyDerivation())
+}
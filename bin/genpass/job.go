@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdJob starts or resumes a batch job that writes unique secrets.
+//
+//	genpass job start|resume --spec spec.yaml
+func cmdJob(args []string) error {
+	fs := flag.NewFlagSet("job", flag.ExitOnError)
+	specPath := fs.String("spec", "job.yaml", "path to the job spec (YAML or JSON)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: genpass job start|resume [--spec spec.yaml]")
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		return errors.New("missing job action")
+	}
+	action := args[0]
+	fs.Parse(args[1:])
+
+	spec, err := loadJobSpec(*specPath)
+	if err != nil {
+		return err
+	}
+
+	var state *genpass.JobState
+	switch action {
+	case "start":
+		state, err = genpass.StartJob(spec)
+		if errors.Is(err, genpass.ErrJobExists) {
+			return fmt.Errorf("%w; use 'genpass job resume' to continue it", err)
+		}
+	case "resume":
+		state, err = genpass.ResumeJob(spec)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown job action %q", action)
+	}
+	if err != nil {
+		if state != nil && state.Progress.Written > 0 {
+			fmt.Fprintf(os.Stderr, "%d of %d secrets written; resume with 'genpass job resume'\n", state.Progress.Written, spec.Count)
+		}
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d secrets written to %s\n", state.Progress.Written, spec.Output)
+	return nil
+}
+
+// loadJobSpec reads a job spec. The spec uses the same field names as the
+// JSON encoding of [genpass.JobSpec].
+func loadJobSpec(path string) (genpass.JobSpec, error) {
+	var spec genpass.JobSpec
+	if err := loadYAML(path, &spec); err != nil {
+		return spec, err
+	}
+	if spec.Count < 1 {
+		return spec, fmt.Errorf("%s: count must be positive", path)
+	}
+	return spec, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdKey generates an SSH or age keypair.
+//
+//	genpass key ed25519|rsa|age [-passphrase] [-comment c] [-bits n] [-o file]
+func cmdKey(args []string) error {
+	fs := flag.NewFlagSet("key", flag.ExitOnError)
+	protect := fs.Bool("passphrase", false, "protect the private key with a generated passphrase")
+	words := fs.Int("words", 6, "number of words in the generated passphrase")
+	comment := fs.String("comment", "", "key comment (SSH keys only)")
+	bits := fs.Int("bits", 4096, "key size (RSA keys only)")
+	out := fs.String("o", "", "write the private key to this file and the public key to file.pub")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: genpass key [flags] ed25519|rsa|age")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return usageError("missing key kind")
+	}
+	kind := genpass.KeyKind(fs.Arg(0))
+	// flags may also follow the kind
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return usagef("unexpected argument %q", fs.Arg(0))
+	}
+	if src := genpass.CurrentEntropySource(); src != genpass.EntropySystem {
+		fmt.Fprintf(os.Stderr, "warning: keypairs are always generated from the %s entropy source, not %s\n", genpass.EntropySystem, src)
+	}
+
+	opts := []genpass.KeypairOption{
+		genpass.WithKeyComment(*comment),
+		genpass.WithRSABits(*bits),
+	}
+	passphrase := ""
+	if *protect {
+		var err error
+		passphrase, err = genpass.NewGenerator(genpass.WithWords(genpass.WordlistEFF, *words), genpass.WithSeparator("-")).Generate()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, genpass.WithKeyPassphrase(passphrase))
+	}
+
+	kp, err := genpass.GenerateKeypair(kind, opts...)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		os.Stdout.Write(kp.PrivateKey)
+		fmt.Fprintf(os.Stderr, "Public key: %s\n", kp.PublicKey)
+	} else {
+		if err := os.WriteFile(*out, kp.PrivateKey, 0o600); err != nil {
+			return err
+		}
+		if err := os.WriteFile(*out+".pub", []byte(kp.PublicKey+"\n"), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Private key: %s\n", *out)
+		fmt.Printf("Public key: %s\n", kp.PublicKey)
+	}
+	if kp.Fingerprint != "" {
+		fmt.Fprintf(os.Stderr, "Fingerprint: %s\n", kp.Fingerprint)
+	}
+	if passphrase != "" {
+		fmt.Fprintf(os.Stderr, "Passphrase: %s\n", passphrase)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+var flagLayout = flag.String("layout", "", "only use characters that are easy to type on these keyboard layouts (comma-separated: us,de,fr,mobile)")
+var flagMobileEasy = flag.Bool("mobile-easy", false, "only use characters that are easy to type on phone keyboards (same as --layout mobile)")
+
+// restrictLayout removes the characters that are hard to type on the layouts
+// selected with --layout and --mobile-easy from set.
+func restrictLayout(set genpass.Charset) genpass.Charset {
+	layout := *flagLayout
+	if *flagMobileEasy {
+		layout = strings.Trim(layout+","+genpass.LayoutMobile, ",")
+	}
+	if layout == "" {
+		return set
+	}
+	if *flagPassphrase {
+		fatal(usageError("--layout and --mobile-easy cannot be used with -p (see --mobile-safe)"))
+	}
+	if *flagMaxLength > 0 {
+		fatal(usageError("--layout and --mobile-easy cannot be used with --max-length"))
+	}
+	chars, err := genpass.LayoutCharset(layout)
+	if err != nil {
+		fatal(fmt.Errorf("%w (available: %s)", err, strings.Join(genpass.Layouts(), ", ")))
+	}
+	return set.Intersect(chars)
+}
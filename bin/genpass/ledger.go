@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/calico32/genpass"
+)
+
+var flagLabel = flag.String("label", "", "label to record the secret under (with --remember)")
+var flagRemember = flag.Bool("remember", false, "record metadata about the secret in the rotation ledger")
+var flagLedger = flag.String("ledger", "", "path to the rotation ledger (default: user config dir)")
+
+func openLedger(path string) (*genpass.Ledger, error) {
+	if path == "" {
+		var err error
+		path, err = genpass.DefaultLedgerPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return genpass.OpenLedger(path)
+}
+
+// remember records secret in the ledger if --remember was given.
+func remember(secret string, entropy float64) {
+	if !*flagRemember {
+		return
+	}
+	err := func() error {
+		ledger, err := openLedger(*flagLedger)
+		if err != nil {
+			return err
+		}
+		if _, err := ledger.Remember(*flagLabel, secret, entropy); err != nil {
+			return err
+		}
+		return ledger.Save()
+	}()
+	if err != nil {
+		fatal(fmt.Errorf("failed to update ledger: %w", err))
+	}
+}
+
+// cmdRotate lists the secrets in the ledger that are due for rotation.
+//
+//	genpass rotate --older-than 90d
+func cmdRotate(args []string) error {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	olderThan := fs.String("older-than", "90d", "list secrets older than this age (e.g. 90d, 12w, 1y, 36h)")
+	ledgerPath := fs.String("ledger", "", "path to the rotation ledger (default: user config dir)")
+	fs.Parse(args)
+
+	age, err := genpass.ParseAge(*olderThan)
+	if err != nil {
+		return fmt.Errorf("invalid age %q", *olderThan)
+	}
+	ledger, err := openLedger(*ledgerPath)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due := ledger.Due(age, now)
+	if len(due) == 0 {
+		fmt.Println("No secrets are due for rotation.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LABEL\tCREATED\tAGE\tLENGTH\tENTROPY")
+	for _, e := range due {
+		days := int(now.Sub(e.Created).Hours() / 24)
+		fmt.Fprintf(w, "%s\t%s\t%dd\t%d\t%.2f bits\n", e.Label, e.Created.Format(time.DateOnly), days, e.Length, e.Entropy)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdLintSecrets scans configuration files for secrets assigned literally to
+// settings like "password" that are common passwords or easy to brute-force.
+// Findings are reported by file and line; the secrets are never printed.
+//
+//	genpass lint-secrets [-min-bits 56] config/ .env
+func cmdLintSecrets(args []string) error {
+	fs := flag.NewFlagSet("lint-secrets", flag.ExitOnError)
+	minBits := fs.Float64("min-bits", genpass.DefaultLintMinBits, "report secrets a mask attack exhausts with fewer bits of work")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return usageError("usage: genpass lint-secrets [-min-bits n] <dir|file>...")
+	}
+
+	found := 0
+	for _, root := range fs.Args() {
+		findings, err := genpass.LintSecretsDir(root, *minBits)
+		if err != nil {
+			return err
+		}
+		for _, f := range findings {
+			detail := fmt.Sprintf("%.1f bits", f.Analysis.Bits)
+			if f.Reason == genpass.LintCommon {
+				detail = "common password"
+			}
+			fmt.Printf("%s:%d: %s: %s (%s)\n", f.Path, f.Line, f.Key, detail, f.Analysis.Mask)
+		}
+		found += len(findings)
+	}
+	if found > 0 {
+		return fmt.Errorf("%d weak secret(s) found", found)
+	}
+	return nil
+}
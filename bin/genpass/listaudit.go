@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdAuditList reports duplicates, near-duplicates, common passwords, and the
+// distribution of entropy in a list of existing secrets, one per line. Secrets
+// are referred to by line number and never printed.
+//
+//	genpass audit-list passwords.txt
+func cmdAuditList(args []string) error {
+	if len(args) > 1 {
+		return usageError("usage: genpass audit-list [file]")
+	}
+	var r io.Reader = os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var secrets []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		secrets = append(secrets, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	a := genpass.AuditList(secrets)
+	fmt.Printf("%d secrets\n", a.Total)
+	printGroups("duplicates", a.Duplicates)
+	printGroups("near-duplicates", a.NearDuplicates)
+	fmt.Printf("\ncommon passwords: %d\n", len(a.Common))
+	if len(a.Common) > 0 {
+		fmt.Printf("  %s\n", formatLines(a.Common))
+	}
+
+	fmt.Println("\nentropy (brute-force estimate):")
+	most := 0
+	for _, n := range a.Histogram {
+		most = max(most, n)
+	}
+	for bin, n := range a.Histogram {
+		lo, hi := a.BinRange(bin)
+		label := fmt.Sprintf("%3.0f-%.0f bits", lo, hi)
+		if math.IsInf(hi, 1) {
+			label = fmt.Sprintf("%3.0f+ bits", lo)
+		}
+		bar := 0
+		if most > 0 {
+			bar = (n*40 + most - 1) / most
+		}
+		fmt.Printf("  %-13s %-40s %d\n", label, strings.Repeat("#", bar), n)
+	}
+
+	if bad := a.Flagged(); bad > 0 {
+		return fmt.Errorf("%d secret(s) are reused or common", bad)
+	}
+	return nil
+}
+
+// printGroups prints groups of secrets by line number.
+func printGroups(title string, groups [][]int) {
+	n := 0
+	for _, g := range groups {
+		n += len(g)
+	}
+	fmt.Printf("\n%s: %d groups (%d secrets)\n", title, len(groups), n)
+	for _, g := range groups {
+		fmt.Printf("  %s\n", formatLines(g))
+	}
+}
+
+// formatLines formats zero-based indexes as line numbers.
+func formatLines(indexes []int) string {
+	lines := make([]string, len(indexes))
+	for i, idx := range indexes {
+		lines[i] = strconv.Itoa(idx + 1)
+	}
+	return "lines " + strings.Join(lines, ", ")
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
-	"encoding/base64"
-	"encoding/hex"
+	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"math"
 	"math/big"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/calico32/genpass"
 
@@ -22,18 +24,38 @@ var flagUpper = flag.Bool("upper", false, "A-Z")
 var flagNumber = flag.Bool("number", false, "0-9")
 var flagSpecial = flag.Bool("special", false, "!@#$%^&*()_+")
 
+var flagSet = flag.String("set", "", "charset expression, e.g. alpha+num-ambiguous or all-[\"'`]")
+var flagCharset = flag.String("charset", "", "use these characters (- to read from stdin)")
+var flagCharsetFile = flag.String("charset-file", "", "read the charset from a file")
+var flagWordlist = flag.String("wordlist", "", "read the passphrase wordlist from a file (- for stdin)")
+var flagWeighted = flag.Bool("weighted", false, "the wordlist has a weight after each word; choose words in proportion to it")
+
+var flagRequire = flag.String("require", "", "require at least one character from each class (comma-separated: lower,upper,digit,special)")
+var flagMaxLength = flag.Int("max-length", 0, "choose the strongest password that fits in this many characters")
+var flagMaxChars = flag.Int("max-chars", 0, "choose the strongest passphrase that fits in this many characters (with -p)")
+
 var flagBytes = flag.Bool("bytes", false, "interpret length as bytes (hex only)")
 var flagBase64 = flag.Bool("base64", false, "show base64 (raw url) encoding of raw bytes (hex only)")
+var flagEncoding = flag.String("encoding", "", "generate length random bytes and print them with this encoding")
+var flagPGPWords = flag.Bool("pgp-words", false, "also show raw bytes as PGP words (hex or --encoding only)")
+var flagLength = flag.String("length", "", "password length, with an optional size suffix (e.g. 10MB)")
+var flagRaw = flag.Bool("raw", false, "write only the password to stdout, without a trailing newline")
+var flagPassphrase = flag.Bool("passphrase", false, "generate a passphrase; length is the number of words")
+var flagWordSep = flag.String("word-separator", "-", "separator between passphrase words")
+var flagCapitalize = flag.Bool("capitalize", false, "capitalize passphrase words")
+var flagLeet = flag.Bool("leet", false, "randomly replace letters in passphrase words with leet-speak digits")
+var flagLeetRate = flag.Float64("leet-rate", 0.3, "probability of each leet-speak replacement")
+var flagMobileSafe = flag.Bool("mobile-safe", false, "only use words and separators that phone autocorrect and smart punctuation leave alone (with -p)")
+var flagAddDigit = flag.Bool("add-digit", false, "append a random digit to a random passphrase word")
+var flagGroup = flag.Int("group", 0, "split the output into groups of this many characters")
+var flagSeparator = flag.String("separator", "-", "separator between groups")
+var flagGroupDisplay = flag.Bool("group-display", false, "only group the output for display; separators are not part of the password")
+var flagQuiet = flag.Bool("quiet", false, "print only the password")
+var flagNoNewline = flag.Bool("no-newline", false, "do not print a trailing newline after the password")
+var flagMinEntropy = flag.Float64("min-entropy", 0, "fail if the configuration provides fewer bits of entropy")
 var flagEntropy = flag.Bool("entropy", false, "show entropy")
 var flagCollisions = flag.Bool("collisions", false, "show collision information")
 
-const (
-	minEntropyWeak       = 28.0
-	minEntropyFair       = 56.0
-	minEntropyStrong     = 84.0
-	minEntropyVeryStrong = 128.0
-)
-
 func init() {
 	getopt.Alias("h", "hex")
 	getopt.Alias("a", "alpha")
@@ -43,100 +65,667 @@ func init() {
 	getopt.Alias("s", "special")
 	getopt.Alias("b", "bytes")
 	getopt.Alias("B", "base64")
+	getopt.Alias("E", "encoding")
+	getopt.Alias("r", "raw")
+	getopt.Alias("p", "passphrase")
+	getopt.Alias("g", "group")
+	getopt.Alias("q", "quiet")
 	getopt.Alias("e", "entropy")
 	getopt.Alias("c", "collisions")
 }
 
 func main() {
+	args := errorFormatArgs(os.Args[1:])
+	checkErrorFormat()
+	loadConfig()
+	if runCommand(args) {
+		return
+	}
+
 	getopt.Parse()
+	checkErrorFormat()
 
-	charset := ""
-	if *flagHex {
-		charset += genpass.CharsetHex
+	if *flagRemember && *flagLabel == "" {
+		fatal(usageError("--remember requires --label"))
 	}
-	if *flagAlpha {
-		charset += genpass.CharsetAlpha
-	}
-	if *flagLower {
-		charset += genpass.CharsetLower
+
+	var set genpass.Charset
+	for _, f := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{*flagHex, genpass.CharsetHex},
+		{*flagAlpha, genpass.CharsetAlpha},
+		{*flagLower, genpass.CharsetLower},
+		{*flagUpper, genpass.CharsetUpper},
+		{*flagNumber, genpass.CharsetNum},
+		{*flagSpecial, genpass.CharsetSpecial},
+	} {
+		if f.enabled {
+			set = set.Union(genpass.NewCharset(f.chars))
+		}
 	}
-	if *flagUpper {
-		charset += genpass.CharsetUpper
+
+	if *flagSet != "" {
+		expr, err := genpass.ParseCharset(*flagSet)
+		if err != nil {
+			fatal(err)
+		}
+		set = set.Union(expr)
 	}
-	if *flagNumber {
-		charset += genpass.CharsetNum
+
+	if *flagCharset != "" || *flagCharsetFile != "" {
+		custom, err := loadCharset()
+		if err != nil {
+			fatal(err)
+		}
+		set = set.Union(genpass.NewCharset(custom))
 	}
-	if *flagSpecial {
-		charset += genpass.CharsetSpecial
+
+	if set.Len() == 0 {
+		set = genpass.NewCharset(genpass.CharsetAll)
 	}
+	set = restrictLayout(set)
+	charset := set.String()
 
-	if charset == "" {
-		charset = genpass.CharsetAll
+	wordlist := genpass.WordlistEFF
+	var weights []int
+	if *flagWeighted && *flagWordlist == "" {
+		fatal(usageError("--weighted requires --wordlist"))
+	}
+	if *flagWordlist != "" {
+		var err error
+		wordlist, weights, err = loadWordlist(*flagWordlist)
+		if err != nil {
+			fatal(err)
+		}
 	}
 
 	length := 16
+	if *flagPassphrase {
+		length = 6
+	}
+	lengthArg := *flagLength
 	if getopt.CommandLine.NArg() > 0 {
-		l, err := strconv.Atoi(getopt.CommandLine.Arg(0))
+		lengthArg = getopt.CommandLine.Arg(0)
+	}
+	pol, hasPolicy := policy()
+	if hasPolicy {
+		if *flagPassphrase {
+			// policies are written for passwords and only configure password
+			// generation
+			fatal(usageError("--policy cannot be used with -p"))
+		}
+		length = pol.Length()
+	}
+	if lengthArg != "" {
+		l, err := parseLength(lengthArg)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "error: invalid length")
-			os.Exit(1)
+			fatal(usageError("invalid length"))
 		}
 		length = l
 	}
+	if *flagBits > 0 && lengthArg != "" {
+		fatal(usageError("--bits cannot be used with a length"))
+	}
+
+	if *flagReveal && *flagShow == 0 {
+		fatal(usageError("--reveal requires --show"))
+	}
+	checkSinks()
+	if *flagSplit != "" && *flagTranscriptionCheck {
+		fatal(usageError("--split cannot be used with --transcription-check"))
+	}
+	if *flagStats && *flagCount == "" {
+		fatal(usageError("--stats requires --count"))
+	}
+	if *flagParity != 0 && *flagEncoding == "" {
+		fatal(usageError("--parity requires --encoding"))
+	}
+	if *flagGrammar != "" {
+		if *flagBits > 0 {
+			fatal(usageError("--bits cannot be used with --grammar"))
+		}
+		if _, emit := outputEmitter(); *flagCount != "" || *flagOutput != "" && !emit {
+			fatal(usageError("--grammar cannot be used with --count or --output"))
+		}
+		generateGrammar()
+		return
+	}
+	if *flagEncoding != "" {
+		if _, emit := outputEmitter(); *flagCount != "" || *flagOutput != "" && !emit {
+			fatal(usageError("--encoding cannot be used with --count or --output"))
+		}
+		if _, ok := genpass.LookupEncoder(*flagEncoding); !ok {
+			fatal(usagef("unknown encoding %q (available: %s)", *flagEncoding, strings.Join(genpass.Encoders(), ", ")))
+		}
+		if strings.EqualFold(*flagEncoding, "proquint") && length%2 != 0 {
+			fatal(usageError("length must be a multiple of 2 for proquint encoding"))
+		}
+		if strings.EqualFold(*flagEncoding, "proquint") && *flagParity%2 != 0 {
+			fatal(usageError("parity must be a multiple of 2 for proquint encoding"))
+		}
+		if *flagBits > 0 {
+			length = genpass.LengthForEntropy(256, *flagBits)
+			if strings.EqualFold(*flagEncoding, "proquint") {
+				length += length % 2
+			}
+			if !*flagQuiet && !*flagRaw {
+				fmt.Fprintf(os.Stderr, "length: %d bytes (%d bits)\n", length, length*8)
+			}
+		}
+		generateEncoded(length)
+		return
+	}
 
 	if *flagBytes && *flagHex {
 		length *= 2
 	}
 
 	if *flagBase64 && *flagHex && length%2 != 0 {
-		fmt.Fprintln(os.Stderr, "error: length must be a multiple of 2 for base64 encoding")
-		os.Exit(1)
+		fatal(usageError("length must be a multiple of 2 for base64 encoding"))
 	}
 
-	charset = genpass.NormalizeCharset(charset)
-	password := genpass.Generate(length, charset)
-
-	fmt.Println(string(password))
+	var required []genpass.Class
+	if *flagRequire != "" {
+		var err error
+		required, err = genpass.ParseClasses(*flagRequire)
+		if err != nil {
+			fatal(err)
+		}
+	}
+	if hasPolicy {
+		if length < pol.MinLength || pol.MaxLength > 0 && length > pol.MaxLength {
+			fatal(usagef("length %d is not allowed by the %s policy", length, pol.Description))
+		}
+		for _, c := range pol.RequiredClasses() {
+			if !slices.Contains(required, c) {
+				required = append(required, c)
+			}
+		}
+	}
 
-	if *flagBase64 && *flagHex {
-		buf := make([]byte, length/2)
-		_, err := hex.Decode(buf, []byte(password))
+	opts := []genpass.Option{
+		genpass.WithCharset(charset),
+		genpass.WithLength(length),
+		genpass.WithRequiredClasses(required...),
+	}
+	if *flagMaxLength > 0 {
+		solution, err := solve(required)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "error: failed to decode hex")
-			os.Exit(1)
+			fatal(err)
+		}
+		charset = solution.Charset
+		opts = solution.Options()
+	}
+	if *flagPassphrase {
+		var transforms []genpass.Transform
+		if *flagCapitalize {
+			transforms = append(transforms, genpass.Capitalize)
+		}
+		if *flagLeet {
+			transforms = append(transforms, genpass.LeetRandom(*flagLeetRate))
+		}
+		if *flagAddDigit {
+			transforms = append(transforms, genpass.InsertDigit)
+		}
+		if *flagMobileSafe {
+			wordlist, weights = mobileSafe(wordlist, weights)
+		}
+		if *flagMaxChars > 0 {
+			wordlist, length = solvePassphrase(wordlist, weights)
+		}
+		opts = append(opts,
+			genpass.WithWords(wordlist, length),
+			genpass.WithSeparator(*flagWordSep),
+			genpass.WithTransforms(transforms...),
+		)
+		if weights != nil {
+			opts = append(opts, genpass.WithWeights(weights))
+		}
+	}
+	if *flagMaxChars > 0 && !*flagPassphrase {
+		fatal(usageError("--max-chars requires -p"))
+	}
+	if *flagMobileSafe && !*flagPassphrase {
+		fatal(usageError("--mobile-safe requires -p"))
+	}
+	opts = append(opts, genpass.WithMinEntropy(*flagMinEntropy))
+	deny, err := denylistOption()
+	if err != nil {
+		fatal(err)
+	}
+	if deny != nil {
+		opts = append(opts, deny)
+	}
+	audit, err := auditOption()
+	if err != nil {
+		fatal(err)
+	}
+	if audit != nil {
+		opts = append(opts, audit)
+	}
+	if hasPolicy {
+		opts = append(opts, genpass.WithPolicyName(pol.Name))
+	}
+	if *flagBits > 0 {
+		if *flagMaxLength > 0 || *flagMaxChars > 0 {
+			fatal(usageError("--bits cannot be used with --max-length or --max-chars"))
+		}
+		length = lengthForBits(opts, charset, wordlist)
+		if hasPolicy {
+			length = max(length, pol.MinLength)
 		}
-		fmt.Printf("base64url: %s\n", base64.RawURLEncoding.EncodeToString(buf))
+		opts = append(opts, lengthOption(length, wordlist))
+	}
+	gen := genpass.NewGenerator(opts...)
+	if err := gen.Validate(); err != nil {
+		fatal(err)
 	}
 
-	if *flagEntropy {
-		e := math.Log2(float64(len(charset))) * float64(length)
-		fmt.Printf("Charset: %s\n", charset)
-		c := "very weak"
-		if e >= minEntropyWeak {
-			c = "weak"
+	count, err := batchCount()
+	if err != nil {
+		fatal(err)
+	}
+	if _, emit := outputEmitter(); count > 0 || *flagOutput != "" && !emit {
+		if emit {
+			fatal(usagef("--count cannot be used with --output %s (use ./%[1]s to write to a file with that name)", *flagOutput))
+		}
+		// these handle a single secret
+		for _, f := range []struct {
+			name string
+			set  bool
+		}{
+			{"--store", *flagStore != ""},
+			{"--show", *flagShow > 0},
+			{"--encrypt-to", *flagEncryptTo != ""},
+			{"--qr", *flagQR != ""},
+			{"--paper-backup", *flagPaperBackup != ""},
+			{"--split", *flagSplit != ""},
+			{"--remember", *flagRemember},
+		} {
+			if f.set {
+				fatal(usagef("%s cannot be used with --count or --output", f.name))
+			}
+		}
+		if err := runBatch(gen, max(count, 1)); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if *flagRaw && rawFastPath() && deny == nil && audit == nil {
+		out := bufio.NewWriter(os.Stdout)
+		if err := genpass.GenerateTo(out, charset, length); err != nil {
+			fatal(err)
+		}
+		if err := out.Flush(); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	password, warnings, err := gen.GenerateWithWarnings()
+	if err != nil {
+		fatal(err)
+	}
+	if !*flagQuiet && !*flagRaw {
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
 		}
-		if e >= minEntropyFair {
-			c = "fair"
+	}
+
+	printSecret(password)
+	remember(password, gen.Entropy())
+	if secretOnly() {
+		return
+	}
+
+	if (*flagBase64 || *flagPGPWords) && *flagHex {
+		buf, err := genpass.DecodeBytes("hex", password)
+		if err != nil {
+			fatal(errors.New("failed to decode hex"))
 		}
-		if e >= minEntropyStrong {
-			c = "strong"
+		if *flagBase64 {
+			encoded, _ := genpass.EncodeBytes("base64url", buf)
+			fmt.Printf("base64url: %s\n", encoded)
 		}
-		if e >= minEntropyVeryStrong {
-			c = "very strong"
+		if *flagPGPWords {
+			fmt.Printf("PGP words: %s\n", genpass.PGPWords.Encode(buf))
 		}
-		fmt.Printf("Entropy: %.2f bits (%s)\n", e, c)
 	}
 
+	if *flagEntropy {
+		e := gen.Entropy()
+		printCharset(charset, wordlist)
+		fmt.Printf("Entropy: %.2f bits (%s)\n", e, genpass.StrengthOf(e))
+	}
+	printDerivation(gen.EntropyDerivation())
+
 	if *flagCollisions {
 		if !*flagEntropy {
 			// need to print charset
-			fmt.Printf("Charset: %s\n", charset)
+			printCharset(charset, wordlist)
 		}
 
-		possibilities := new(big.Int).Exp(big.NewInt(int64(len(charset))), big.NewInt(int64(length)), nil)
+		possibilities := gen.Possibilities()
 		fmt.Printf("Possible passwords: %s\n", possibilities.String())
 
 		collisions := genpass.GetCollisionSeconds(possibilities)
 		fmt.Printf("Time until 1%% chance of at least one collision: %s\n", genpass.FormatDuration(collisions))
 	}
 }
+
+// solve picks the strongest format within --max-length, allowing the classes
+// selected with -l, -u, -n, -s, and -a (or all classes if none are selected).
+func solve(required []genpass.Class) (genpass.Solution, error) {
+	if *flagHex || *flagSet != "" || *flagCharset != "" || *flagCharsetFile != "" || *flagPassphrase {
+		return genpass.Solution{}, usageError("--max-length can only be combined with -l, -u, -n, -s, and -a")
+	}
+
+	var allowed []genpass.Class
+	if *flagLower || *flagAlpha {
+		allowed = append(allowed, genpass.ClassLower)
+	}
+	if *flagUpper || *flagAlpha {
+		allowed = append(allowed, genpass.ClassUpper)
+	}
+	if *flagNumber {
+		allowed = append(allowed, genpass.ClassDigit)
+	}
+	if *flagSpecial {
+		allowed = append(allowed, genpass.ClassSpecial)
+	}
+
+	return genpass.Solve(genpass.Constraints{
+		MaxLength: *flagMaxLength,
+		Required:  required,
+		Allowed:   allowed,
+	})
+}
+
+// mobileSafe removes the words phone keyboards are likely to mangle from
+// wordlist, along with their weights, and checks that the word separator is
+// safe.
+func mobileSafe(wordlist []string, weights []int) ([]string, []int) {
+	if !genpass.IsMobileSafeSeparator(*flagWordSep) {
+		fatal(usagef("word separator %q is changed by smart punctuation on phones", *flagWordSep))
+	}
+	var words []string
+	var ws []int
+	for i, w := range wordlist {
+		if !genpass.IsMobileSafeWord(w) {
+			continue
+		}
+		words = append(words, w)
+		if weights != nil {
+			ws = append(ws, weights[i])
+		}
+	}
+	return words, ws
+}
+
+// solvePassphrase picks the word count and the words of wordlist to use for
+// the strongest passphrase within --max-chars. It returns the filtered
+// wordlist and the word count.
+func solvePassphrase(wordlist []string, weights []int) ([]string, int) {
+	if weights != nil {
+		fatal(usageError("--max-chars cannot be combined with --weighted"))
+	}
+	extra := 0
+	if *flagAddDigit {
+		extra = 1
+	}
+	solution, err := genpass.SolvePassphrase(genpass.PassphraseConstraints{
+		MaxChars:  *flagMaxChars,
+		Wordlist:  wordlist,
+		Separator: *flagWordSep,
+		Extra:     extra,
+	})
+	if err != nil {
+		fatal(err)
+	}
+	return solution.Wordlist, solution.Words
+}
+
+// printSecret prints a generated secret, grouping it if requested. With
+// --raw, display-only grouping is not applied. With --store or an emitter
+// selected with --output, the secret is saved or emitted instead. The secret is
+// checked against --policy first.
+func printSecret(secret string) {
+	checkPolicy(secret)
+	if *flagPaperBackup != "" {
+		writePaperBackup(secret)
+		return
+	}
+	emitter, emit := outputEmitter()
+	if *flagGroup > 0 && !((*flagRaw || emit || *flagStore != "") && *flagGroupDisplay) {
+		secret = genpass.Group(secret, *flagGroup, *flagSeparator)
+	}
+	if *flagStore != "" {
+		storeSecret(secret)
+		return
+	}
+	if emit {
+		emitSecret(emitter, secret)
+		return
+	}
+	if *flagSplit != "" {
+		printShares(secret)
+		return
+	}
+	if *flagTranscriptionCheck {
+		secret = genpass.AppendChecksum(secret)
+	}
+	if *flagEncryptTo != "" {
+		printEncrypted(secret)
+		return
+	}
+	if *flagQR != "" {
+		writeQR(secret)
+		return
+	}
+	if *flagShow > 0 {
+		showSecret(secret)
+		return
+	}
+	if *flagRaw || *flagNoNewline {
+		fmt.Print(secret)
+		return
+	}
+	fmt.Println(secret)
+}
+
+// rawFastFlags are the flags that --raw output can be written with
+// genpass.GenerateTo while they are set, since they only select the charset
+// and length or don't apply to raw output.
+var rawFastFlags = []string{
+	"raw", "length", "bits", "min-entropy",
+	"hex", "alpha", "lower", "upper", "number", "special",
+	"set", "charset", "charset-file", "bytes",
+	"quiet", "no-newline", "entropy", "collisions", "error-format",
+}
+
+// rawFastPath reports whether only flags in rawFastFlags are set. getopt sets
+// flag values directly, so set flags are those that differ from their default.
+func rawFastPath() bool {
+	fast := true
+	flag.VisitAll(func(f *flag.Flag) {
+		if f.Value.String() != f.DefValue && !slices.Contains(rawFastFlags, f.Name) {
+			fast = false
+		}
+	})
+	return fast
+}
+
+// checkSinks exits with a usage error if more than one of the outputs that
+// replace printing a single secret is selected, since printSecret only uses
+// one of them.
+func checkSinks() {
+	_, emit := outputEmitter()
+	var selected []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"--paper-backup", *flagPaperBackup != ""},
+		{"--store", *flagStore != ""},
+		{"--output", emit},
+		{"--split", *flagSplit != ""},
+		{"--encrypt-to", *flagEncryptTo != ""},
+		{"--qr", *flagQR != ""},
+		{"--show", *flagShow > 0},
+	} {
+		if f.set {
+			selected = append(selected, f.name)
+		}
+	}
+	if len(selected) > 1 {
+		fatal(usagef("%s cannot be used together", strings.Join(selected, " and ")))
+	}
+}
+
+// secretOnly reports whether the output options leave no room for the
+// information printed after the secret, such as its entropy.
+func secretOnly() bool {
+	_, emit := outputEmitter()
+	return *flagRaw || *flagQuiet || *flagStore != "" || *flagQR != "" || *flagPaperBackup != "" || *flagEncryptTo != "" || *flagSplit != "" || emit
+}
+
+// generateEncoded prints n random bytes using the encoding selected with
+// --encoding.
+func generateEncoded(n int) {
+	if e := float64(n) * 8; e < *flagMinEntropy {
+		err := &genpass.EntropyError{Entropy: e, Min: *flagMinEntropy}
+		fatal(err)
+	}
+
+	buf, err := genpass.GenerateBytes(n)
+	if err != nil {
+		fatal(err)
+	}
+	buf = addParity(buf)
+	encoded, err := genpass.EncodeBytes(*flagEncoding, buf)
+	if err != nil {
+		fatal(err)
+	}
+
+	printSecret(encoded)
+	remember(encoded, float64(n)*8)
+	if secretOnly() {
+		return
+	}
+
+	if *flagPGPWords {
+		fmt.Printf("PGP words: %s\n", genpass.PGPWords.Encode(buf))
+	}
+
+	if *flagEntropy || *flagCollisions {
+		fmt.Printf("Encoding: %s (%d bytes)\n", *flagEncoding, n)
+	}
+	if *flagEntropy {
+		e := float64(n) * 8
+		fmt.Printf("Entropy: %.2f bits (%s)\n", e, genpass.StrengthOf(e))
+	}
+	printDerivation([]genpass.EntropyTerm{{
+		Source:  *flagEncoding + " bytes",
+		Formula: fmt.Sprintf("%d × 8", n),
+		Bits:    float64(n) * 8,
+	}})
+	if *flagCollisions {
+		possibilities := new(big.Int).Lsh(big.NewInt(1), uint(n)*8)
+		fmt.Printf("Possible passwords: %s\n", possibilities.String())
+
+		collisions := genpass.GetCollisionSeconds(possibilities)
+		fmt.Printf("Time until 1%% chance of at least one collision: %s\n", genpass.FormatDuration(collisions))
+	}
+}
+
+func printCharset(charset string, wordlist []string) {
+	if *flagPassphrase {
+		name := "EFF"
+		if *flagWordlist != "" {
+			name = *flagWordlist
+		}
+		weighted := ""
+		if *flagWeighted {
+			weighted = ", weighted"
+		}
+		fmt.Printf("Wordlist: %s (%d words%s)\n", name, len(wordlist), weighted)
+		return
+	}
+	fmt.Printf("Charset: %s\n", charset)
+}
+
+// loadCharset loads the charset given with --charset or --charset-file.
+func loadCharset() (string, error) {
+	switch {
+	case *flagCharset == "-":
+		return genpass.LoadCharset(os.Stdin)
+	case *flagCharset != "":
+		return genpass.LoadCharset(strings.NewReader(*flagCharset))
+	}
+
+	f, err := os.Open(*flagCharsetFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return genpass.LoadCharset(f)
+}
+
+func loadWordlist(path string) ([]string, []int, error) {
+	r := os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	if *flagWeighted {
+		return genpass.LoadWeightedWordlist(r)
+	}
+	words, err := genpass.LoadWordlist(r)
+	return words, nil, err
+}
+
+var sizeSuffixes = []struct {
+	suffix string
+	mul    int
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1000},
+	{"MB", 1000 * 1000},
+	{"GB", 1000 * 1000 * 1000},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+}
+
+// parseLength parses a length that may have a size suffix like "10MB" or
+// "4KiB". Plain K/M/G suffixes are binary multiples.
+func parseLength(s string) (int, error) {
+	mul := 1
+	for _, u := range sizeSuffixes {
+		if strings.HasSuffix(strings.ToUpper(s), strings.ToUpper(u.suffix)) {
+			s = s[:len(s)-len(u.suffix)]
+			mul = u.mul
+			break
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("negative length")
+	}
+	if n > math.MaxInt/mul {
+		return 0, usageError("length is too large")
+	}
+	return n * mul, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdMnemonic generates a BIP-39 mnemonic, or decodes one back to hex.
+//
+//	genpass mnemonic [--bits 128]
+//	genpass mnemonic --decode WORD...
+func cmdMnemonic(args []string) error {
+	fs := flag.NewFlagSet("mnemonic", flag.ExitOnError)
+	bits := fs.Int("bits", 128, "bits of entropy (128, 160, 192, 224, or 256)")
+	decode := fs.Bool("decode", false, "decode the mnemonic given as arguments to hex")
+	fs.Parse(args)
+
+	if *decode {
+		entropy, err := genpass.MnemonicToBytes(strings.Join(fs.Args(), " "))
+		if err != nil {
+			return err
+		}
+		fmt.Println(hex.EncodeToString(entropy))
+		return nil
+	}
+
+	mnemonic, err := genpass.GenerateMnemonic(*bits)
+	if err != nil {
+		return err
+	}
+	fmt.Println(mnemonic)
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+var flagPaperBackup = flag.String("paper-backup", "", "write a printable PDF backup sheet of the secret to this file instead of printing it")
+
+// writePaperBackup writes secret as a paper backup sheet to the file given
+// with --paper-backup. The secret is grouped on the sheet with --group, or in
+// groups of 4 characters if it isn't a passphrase.
+func writePaperBackup(secret string) {
+	group := *flagGroup
+	if group == 0 && !*flagPassphrase {
+		group = 4
+	}
+	err := replaceFile(*flagPaperBackup, func() error {
+		f, err := os.OpenFile(*flagPaperBackup, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		err = genpass.WritePaperBackup(f, genpass.PaperBackup{
+			Title:     *flagName,
+			Secret:    secret,
+			GroupSize: group,
+		})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	})
+	if err != nil {
+		fatal(fmt.Errorf("failed to write paper backup: %w", err))
+	}
+	if !*flagQuiet {
+		fmt.Fprintf(os.Stderr, "Wrote paper backup to %s\n", *flagPaperBackup)
+	}
+}
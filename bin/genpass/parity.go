@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/calico32/genpass"
+)
+
+var flagParity = flag.Int("parity", 0, "add this many Reed-Solomon parity bytes per block so damaged copies can be repaired with rs-decode (with --encoding)")
+
+// addParity adds the Reed-Solomon parity requested with --parity to buf.
+func addParity(buf []byte) []byte {
+	if *flagParity == 0 {
+		return buf
+	}
+	code, err := genpass.EncodeReedSolomon(buf, *flagParity)
+	if err != nil {
+		fatal(err)
+	}
+	return code
+}
+
+// cmdRSDecode repairs key material generated with --parity and prints it
+// without the parity bytes. The encoded text is read from the arguments or
+// standard input; whitespace is ignored.
+//
+//	genpass rs-decode -parity 8 -encoding base32 TEXT
+func cmdRSDecode(args []string) error {
+	fs := flag.NewFlagSet("rs-decode", flag.ExitOnError)
+	parity := fs.Int("parity", 0, "number of parity bytes per block the text was generated with")
+	encoding := fs.String("encoding", "hex", "encoding the text was generated with")
+	fs.Parse(args)
+	if *parity == 0 {
+		return usageError("usage: genpass rs-decode -parity N [-encoding ENCODING] [TEXT]")
+	}
+
+	text := strings.Join(fs.Args(), "")
+	if fs.NArg() == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		text = string(b)
+	}
+	text = strings.Join(strings.FieldsFunc(text, unicode.IsSpace), "")
+
+	code, err := genpass.DecodeBytes(*encoding, text)
+	if err != nil {
+		return err
+	}
+	data, corrected, err := genpass.DecodeReedSolomon(code, *parity)
+	if err != nil {
+		return err
+	}
+	encoded, err := genpass.EncodeBytes(*encoding, data)
+	if err != nil {
+		return err
+	}
+	fmt.Println(encoded)
+	if corrected > 0 {
+		fmt.Fprintf(os.Stderr, "Corrected %d damaged bytes\n", corrected)
+	}
+	return nil
+}
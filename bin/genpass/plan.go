@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/calico32/genpass"
+	"gopkg.in/yaml.v3"
+)
+
+// planSymbols mark the steps of a printed plan.
+var planSymbols = map[genpass.PlanAction]string{
+	genpass.PlanCreate: "+",
+	genpass.PlanRotate: "~",
+	genpass.PlanSkip:   " ",
+}
+
+// cmdPlan prints the secrets that "genpass apply" would create or rotate to
+// make the secret stores match a manifest.
+//
+//	genpass plan secrets.yaml
+func cmdPlan(args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	ledgerPath := fs.String("ledger", "", "path to the rotation ledger (default: user config dir)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return usageError("usage: genpass plan [-ledger path] manifest.yaml")
+	}
+
+	plan, _, err := loadPlan(fs.Arg(0), *ledgerPath)
+	if err != nil {
+		return err
+	}
+	return printPlan(os.Stdout, plan)
+}
+
+// applySummary is a result of cmdApply in its JSON summary.
+type applySummary struct {
+	Name      string  `json:"name"`
+	Store     string  `json:"store"`
+	Action    string  `json:"action"`
+	Attempts  int     `json:"attempts"`
+	Seconds   float64 `json:"seconds"`
+	Error     string  `json:"error,omitempty"`
+	Ambiguous bool    `json:"ambiguous,omitempty"`
+}
+
+// cmdApply creates and rotates secrets to make the secret stores match a
+// manifest, after showing the plan and asking for confirmation. Secrets are
+// pushed in parallel, following the timeouts and retries configured for each
+// store in the manifest, and a summary of the results is printed at the end.
+//
+//	genpass apply [-yes] [-parallel 8] [-json] secrets.yaml
+func cmdApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	ledgerPath := fs.String("ledger", "", "path to the rotation ledger (default: user config dir)")
+	yes := fs.Bool("yes", false, "apply the plan without asking for confirmation")
+	parallel := fs.Int("parallel", 8, "number of secrets pushed at once")
+	asJSON := fs.Bool("json", false, "print the summary as JSON, and the plan to stderr")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return usageError("usage: genpass apply [-ledger path] [-yes] [-parallel n] [-json] manifest.yaml")
+	}
+
+	plan, ledger, err := loadPlan(fs.Arg(0), *ledgerPath)
+	if err != nil {
+		return err
+	}
+	var out io.Writer = os.Stdout
+	if *asJSON {
+		out = os.Stderr
+	}
+	if err := printPlan(out, plan); err != nil {
+		return err
+	}
+	if plan.Count(genpass.PlanCreate)+plan.Count(genpass.PlanRotate) == 0 {
+		return nil
+	}
+	if !*yes {
+		fmt.Fprint(out, "\nApply this plan? [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return errors.New("apply cancelled")
+		}
+	}
+	report := plan.ApplyParallel(ledger, *parallel)
+	if *asJSON {
+		err = printApplyJSON(report)
+	} else {
+		err = printApplyReport(report)
+	}
+	if err != nil {
+		return err
+	}
+	if n := report.Failed(); n > 0 {
+		msg := fmt.Sprintf("%d of %d secret(s) failed", n, len(report.Results))
+		if a := report.Ambiguous(); a > 0 {
+			msg += fmt.Sprintf(", %d timed out and may still have been stored", a)
+		}
+		return summarizedError{msg, report.Err()}
+	}
+	return nil
+}
+
+// summarizedError is reported in place of errors that were already printed,
+// keeping them available to exitCode.
+type summarizedError struct {
+	msg string
+	err error
+}
+
+func (e summarizedError) Error() string { return e.msg }
+
+func (e summarizedError) Unwrap() error { return e.err }
+
+// printApplyReport prints the result of each step and the number of
+// successes and failures per store. Failures that may still have stored the
+// secret are counted as unknown.
+func printApplyReport(report genpass.ApplyReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nNAME\tSTORE\tACTION\tRESULT\tATTEMPTS\tTIME")
+	type counts struct{ ok, failed, unknown int }
+	stores := map[string]*counts{}
+	for _, r := range report.Results {
+		store := r.Step.Secret.StoreName()
+		c := stores[store]
+		if c == nil {
+			c = &counts{}
+			stores[store] = c
+		}
+		result := "ok"
+		switch {
+		case r.Ambiguous:
+			result = "unknown: " + strings.TrimPrefix(errors.Unwrap(r.Err).Error(), "genpass: ")
+			c.unknown++
+		case r.Err != nil:
+			result = "failed: " + strings.TrimPrefix(errors.Unwrap(r.Err).Error(), "genpass: ")
+			c.failed++
+		default:
+			c.ok++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%v\n", r.Step.Secret.Name, store, r.Step.Action, result, r.Attempts, r.Duration.Round(time.Millisecond))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println()
+	for _, name := range slices.Sorted(maps.Keys(stores)) {
+		c := stores[name]
+		fmt.Printf("%s: %d ok, %d failed, %d unknown\n", name, c.ok, c.failed, c.unknown)
+	}
+	return nil
+}
+
+// printApplyJSON prints the result of each step as a JSON array.
+func printApplyJSON(report genpass.ApplyReport) error {
+	summary := make([]applySummary, len(report.Results))
+	for i, r := range report.Results {
+		summary[i] = applySummary{
+			Name:      r.Step.Secret.Name,
+			Store:     r.Step.Secret.StoreName(),
+			Action:    string(r.Step.Action),
+			Attempts:  r.Attempts,
+			Seconds:   r.Duration.Seconds(),
+			Ambiguous: r.Ambiguous,
+		}
+		if r.Err != nil {
+			summary[i].Error = errors.Unwrap(r.Err).Error()
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(summary)
+}
+
+// loadPlan reads the manifest at path and plans it against the ledger.
+func loadPlan(path, ledgerPath string) (genpass.Plan, *genpass.Ledger, error) {
+	manifest, err := loadManifest(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	ledger, err := openLedger(ledgerPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan, err := manifest.Plan(ledger, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	return plan, ledger, nil
+}
+
+// printPlan prints one line per secret and a summary to out.
+func printPlan(out io.Writer, plan genpass.Plan) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, step := range plan {
+		age := "-"
+		if step.Age > 0 {
+			age = fmt.Sprintf("%dd", int(step.Age.Hours()/24))
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\t%s\n", planSymbols[step.Action], step.Action, step.Secret.Name, step.Secret.StoreName(), age, step.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nPlan: %d to create, %d to rotate, %d unchanged.\n", plan.Count(genpass.PlanCreate), plan.Count(genpass.PlanRotate), plan.Count(genpass.PlanSkip))
+	return nil
+}
+
+// loadManifest reads a manifest. The manifest uses the same field names as
+// the JSON encoding of [genpass.Manifest].
+func loadManifest(path string) (genpass.Manifest, error) {
+	var m genpass.Manifest
+	err := loadYAML(path, &m)
+	return m, err
+}
+
+// loadYAML decodes the YAML file at path into v using v's json tags. Unknown
+// keys are an error, so that a misspelled field is not silently ignored.
+func loadYAML(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	// round-trip through JSON so the struct's json tags apply
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package main
+
+// Packages that register custom schemes with genpass.RegisterScheme are
+// imported here for their side effects, which makes each scheme available
+// as "genpass gen <name>":
+//
+//	import _ "example.com/corp/genpass-schemes"
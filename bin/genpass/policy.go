@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+var flagPolicy = flag.String("policy", "", "generate passwords that satisfy a standard policy (nist, pci, ad-complexity)")
+
+// policy returns the policy selected with --policy, if any.
+func policy() (genpass.Policy, bool) {
+	if *flagPolicy == "" {
+		return genpass.Policy{}, false
+	}
+	p, ok := genpass.LookupPolicy(*flagPolicy)
+	if !ok {
+		fatal(usagef("unknown policy %q (available: %s)", *flagPolicy, strings.Join(genpass.Policies(), ", ")))
+	}
+	return p, true
+}
+
+// checkPolicy exits with an error if secret violates the policy selected with
+// --policy.
+func checkPolicy(secret string) {
+	p, ok := policy()
+	if !ok {
+		return
+	}
+	err := p.Check(secret)
+	if err == nil {
+		return
+	}
+	if *flagErrorFormat == "json" {
+		fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "error: generated secret does not satisfy the %s policy:\n", p.Description)
+	for _, v := range err.(*genpass.PolicyError).Violations {
+		fmt.Fprintf(os.Stderr, "  - %s\n", v)
+	}
+	os.Exit(exitCode(err))
+}
+
+// cmdValidate checks passwords read from stdin, one per line, against a policy.
+// It fails if any password violates the policy.
+//
+//	genpass validate --policy nist < passwords.txt
+func cmdValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	name := fs.String("policy", "nist", "policy to check against ("+strings.Join(genpass.Policies(), ", ")+")")
+	fs.Parse(args)
+
+	p, ok := genpass.LookupPolicy(*name)
+	if !ok {
+		return fmt.Errorf("unknown policy %q", *name)
+	}
+
+	failed := 0
+	scanner := bufio.NewScanner(os.Stdin)
+	for line := 1; scanner.Scan(); line++ {
+		violations := p.Validate(scanner.Text())
+		if len(violations) == 0 {
+			continue
+		}
+		failed++
+		msgs := make([]string, len(violations))
+		for i, v := range violations {
+			msgs[i] = v.Message
+		}
+		fmt.Printf("line %d: %s\n", line, strings.Join(msgs, "; "))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d password(s) violate the %s policy", genpass.ErrPolicyViolation, failed, p.Description)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+var flagQR = flag.String("qr", "", "write the secret as a QR code PNG to this file instead of printing it")
+
+// writeQR writes secret as a QR code to the file given with --qr.
+func writeQR(secret string) {
+	png, err := genpass.EncodeQR(secret)
+	if err == nil {
+		err = replaceFile(*flagQR, func() error {
+			return os.WriteFile(*flagQR, png, 0o600)
+		})
+	}
+	if err != nil {
+		fatal(fmt.Errorf("failed to write QR code: %w", err))
+	}
+	if !*flagQuiet {
+		fmt.Fprintf(os.Stderr, "Wrote QR code to %s\n", *flagQR)
+	}
+}
+
+// cmdQRDecode prints the secret in a QR code image written with --qr.
+//
+//	genpass qr-decode FILE
+func cmdQRDecode(args []string) error {
+	fs := flag.NewFlagSet("qr-decode", flag.ExitOnError)
+	verify := fs.Bool("verify", false, "verify the transcription checksum of the secret and strip it")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return usageError("usage: genpass qr-decode [-verify] FILE")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return err
+	}
+
+	secret, err := genpass.DecodeQR(img)
+	if err != nil {
+		return err
+	}
+	if *verify {
+		secret, err = genpass.VerifyChecksum(secret)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println(secret)
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdRecovery prints a batch of one-time recovery codes.
+//
+//	genpass recovery [--count 10] [--pattern xxxxx-xxxxx] [--hashes file]
+func cmdRecovery(args []string) error {
+	fs := flag.NewFlagSet("recovery", flag.ExitOnError)
+	count := fs.Int("count", 10, "number of codes to generate")
+	pattern := fs.String("pattern", genpass.DefaultRecoveryPattern, "code pattern; each x is replaced with a random character")
+	hashes := fs.String("hashes", "", "also write bcrypt hashes of the codes to this file, one per line, for server-side storage")
+	fs.Parse(args)
+
+	codes, err := genpass.GenerateRecoveryCodes(*count, *pattern)
+	if err != nil {
+		return err
+	}
+
+	if *hashes != "" {
+		var b strings.Builder
+		for _, code := range codes {
+			hash, err := genpass.HashRecoveryCode(code)
+			if err != nil {
+				return err
+			}
+			b.WriteString(hash + "\n")
+		}
+		if err := os.WriteFile(*hashes, []byte(b.String()), 0o600); err != nil {
+			return err
+		}
+	}
+
+	for _, code := range codes {
+		fmt.Println(code)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdSelftest generates a large sample and tests it for bias.
+//
+//	genpass selftest [-samples 200000] [-length 16] [-set all]
+func cmdSelftest(args []string) error {
+	fs := flag.NewFlagSet("selftest", flag.ExitOnError)
+	samples := fs.Int("samples", 200000, "number of passwords to generate")
+	length := fs.Int("length", 16, "password length")
+	set := fs.String("set", "all", "charset expression to test")
+	alpha := fs.Float64("alpha", 0.001, "significance level")
+	verbose := fs.Bool("v", false, "print the results for every position")
+	fs.Parse(args)
+
+	charset, err := genpass.ParseCharset(*set)
+	if err != nil {
+		return err
+	}
+	gen := genpass.NewGenerator(genpass.WithCharset(charset.String()), genpass.WithLength(*length))
+	if err := gen.Validate(); err != nil {
+		return err
+	}
+
+	report := genpass.BiasTest(func() string {
+		s, err := gen.Generate()
+		if err != nil {
+			fatal(err)
+		}
+		return s
+	}, *samples)
+
+	if *verbose {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "POSITION\tCHI-SQUARE\tP\tSERIAL R\tSERIAL P")
+		for _, p := range report.Positions {
+			fmt.Fprintf(w, "%d\t%.1f (df %d)\t%.4f\t%+.5f\t%.4f\n", p.Position+1, p.ChiSquare, p.DF, p.P, p.SerialCorrelation, p.SerialP)
+		}
+		w.Flush()
+	}
+
+	fmt.Printf("%d samples, %d characters, %d tests, min p-value %.4g\n", report.Samples, report.Alphabet.Len(), report.Tests(), report.MinP())
+	if charset.Len() != report.Alphabet.Len() {
+		return fmt.Errorf("only %d of %d characters were generated", report.Alphabet.Len(), charset.Len())
+	}
+	if !report.Passed(*alpha) {
+		return fmt.Errorf("bias detected at significance level %g", *alpha)
+	}
+	fmt.Println("PASS")
+	return nil
+}
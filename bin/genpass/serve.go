@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/calico32/genpass"
+
+	"golang.org/x/time/rate"
+)
+
+// maxServeCount and maxServeLength bound the work a single request can ask
+// for.
+const (
+	maxServeCount  = 1000
+	maxServeLength = 4096
+)
+
+// generateRequest is the body of POST /v1/generate. It extends
+// [genpass.Config] with the number of secrets and a policy to satisfy.
+type generateRequest struct {
+	genpass.Config
+	Count  int    `json:"count,omitempty"`
+	Policy string `json:"policy,omitempty"`
+}
+
+type generateResponse struct {
+	Secrets  []string `json:"secrets"`
+	Entropy  float64  `json:"entropy"`
+	Strength string   `json:"strength"`
+}
+
+type errorResponse struct {
+	Error      string   `json:"error"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// cmdServe serves an HTTP/JSON API for generating secrets.
+//
+//	genpass serve [-listen :8080] [-rate 10] [-burst 20]
+func cmdServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := fs.String("listen", "localhost:8080", "address to listen on")
+	perSecond := fs.Float64("rate", 10, "requests per second allowed per client address")
+	burst := fs.Int("burst", 20, "requests a client may make at once before being rate limited")
+	fs.Parse(args)
+
+	limiter := newClientLimiter(rate.Limit(*perSecond), *burst)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /v1/generate", handleGenerate)
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           logRequests(limiter.middleware(mux)),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	log.Printf("listening on %s", *listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleGenerate generates secrets for POST /v1/generate. An empty body
+// generates one password with the default configuration.
+func handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req generateRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request: " + err.Error()})
+		return
+	}
+
+	count := max(req.Count, 1)
+	if count > maxServeCount {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("count must be at most %d", maxServeCount)})
+		return
+	}
+	if req.Length > maxServeLength || req.Words > maxServeLength {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("length must be at most %d", maxServeLength)})
+		return
+	}
+
+	opts, err := req.Options()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	var policy genpass.Policy
+	if req.Policy != "" {
+		var ok bool
+		policy, ok = genpass.LookupPolicy(req.Policy)
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("unknown policy %q", req.Policy)})
+			return
+		}
+		if req.Words == 0 {
+			required := policy.RequiredClasses()
+			for _, name := range req.Require {
+				if c, err := genpass.ParseClass(name); err == nil && !slices.Contains(required, c) {
+					required = append(required, c)
+				}
+			}
+			opts = append(opts, genpass.WithRequiredClasses(required...))
+			if req.Length == 0 {
+				opts = append(opts, genpass.WithLength(policy.Length()))
+			}
+		}
+	}
+
+	gen := genpass.NewGenerator(opts...)
+	if err := gen.Validate(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := generateResponse{
+		Secrets:  make([]string, count),
+		Entropy:  gen.Entropy(),
+		Strength: genpass.StrengthOf(gen.Entropy()).String(),
+	}
+	for i := range resp.Secrets {
+		secret, err := gen.Generate()
+		if err == nil {
+			err = policy.Check(secret)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp.Secrets[i] = secret
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeError writes err as a JSON error response, with a status code that
+// reflects its cause.
+func writeError(w http.ResponseWriter, err error) {
+	var resp errorResponse
+	var status int
+	var perr *genpass.PolicyError
+	switch {
+	case errors.As(err, &perr):
+		status = http.StatusUnprocessableEntity
+		resp.Error = fmt.Sprintf("configuration does not satisfy the %s policy", perr.Policy.Description)
+		for _, v := range perr.Violations {
+			resp.Violations = append(resp.Violations, v.Message)
+		}
+	case errors.Is(err, genpass.ErrKeyspaceExhausted):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, genpass.ErrEntropySource):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, genpass.ErrSinkUnavailable):
+		status = http.StatusBadGateway
+	default:
+		// anything else is a configuration the request asked for
+		status = http.StatusBadRequest
+	}
+	if resp.Error == "" {
+		resp.Error = err.Error()
+	}
+	writeJSON(w, status, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// statusRecorder records the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs the method, path, status, and duration of every request.
+// Request and response bodies, which may contain secrets, are never logged.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("%s %s %s %d %s", r.RemoteAddr, r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Microsecond))
+	})
+}
+
+// clientLimiter rate limits requests per client IP address.
+type clientLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastSeen  map[string]time.Time
+	lastSweep time.Time
+}
+
+func newClientLimiter(limit rate.Limit, burst int) *clientLimiter {
+	return &clientLimiter{
+		limit:    limit,
+		burst:    burst,
+		limiters: map[string]*rate.Limiter{},
+		lastSeen: map[string]time.Time{},
+	}
+}
+
+func (c *clientLimiter) allow(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	// forget clients that have been idle long enough to have a full bucket
+	if now.Sub(c.lastSweep) > time.Minute {
+		for h, t := range c.lastSeen {
+			if now.Sub(t) > time.Minute {
+				delete(c.limiters, h)
+				delete(c.lastSeen, h)
+			}
+		}
+		c.lastSweep = now
+	}
+	l, ok := c.limiters[host]
+	if !ok {
+		l = rate.NewLimiter(c.limit, c.burst)
+		c.limiters[host] = l
+	}
+	c.lastSeen[host] = now
+	return l.Allow()
+}
+
+func (c *clientLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.allow(r.RemoteAddr) {
+			writeJSON(w, http.StatusTooManyRequests, errorResponse{Error: "rate limit exceeded"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var flagShow = flag.Duration("show", 0, "show the secret on the terminal for this long (e.g. 10s), then erase it")
+var flagReveal = flag.Bool("reveal", false, "wait for enter before showing the secret (with --show)")
+
+// ANSI escapes used by showSecret. The alternate screen keeps the secret out
+// of the scrollback, which an erased line on the main screen would not.
+const (
+	ansiAltScreen  = "\x1b[?1049h"
+	ansiMainScreen = "\x1b[?1049l"
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiEraseLine  = "\r\x1b[2K"
+)
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// showSecret displays secret on the terminal for the duration given with
+// --show and erases it afterwards, or as soon as genpass is interrupted. If
+// stdout is not a terminal, there is nothing to erase, so the secret is
+// printed normally.
+func showSecret(secret string) {
+	if !isTerminal(os.Stdout) {
+		fmt.Fprintln(os.Stderr, "warning: --show needs a terminal; printing the secret without erasing it")
+		fmt.Println(secret)
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+
+	fmt.Print(ansiAltScreen + ansiClear)
+	defer fmt.Print(ansiClear + ansiMainScreen)
+
+	if *flagReveal {
+		fmt.Print("press enter to reveal the secret")
+		entered := make(chan struct{})
+		go func() {
+			waitForEnter()
+			close(entered)
+		}()
+		select {
+		case <-entered:
+		case <-sigs:
+			return
+		}
+		fmt.Print(ansiClear)
+	}
+
+	fmt.Println(secret)
+	fmt.Println()
+	deadline := time.Now().Add(*flagShow)
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		left := time.Until(deadline).Round(time.Second)
+		if left <= 0 {
+			return
+		}
+		fmt.Printf("%serasing in %v (ctrl-c to erase now)", ansiEraseLine, left)
+		select {
+		case <-tick.C:
+		case <-sigs:
+			return
+		}
+	}
+}
+
+// waitForEnter reads a line from the terminal, which stdin may not be if it is
+// used for --charset or --wordlist.
+func waitForEnter() {
+	in := os.Stdin
+	if tty, err := os.Open("/dev/tty"); err == nil {
+		defer tty.Close()
+		in = tty
+	}
+	bufio.NewReader(in).ReadString('\n')
+}
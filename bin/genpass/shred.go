@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+var flagShredPrevious = flag.Bool("shred-previous", false, "shred the file previously at --output, --qr, or --paper-backup once the new secret is written")
+
+// replaceFile calls write to write a secret to path. With --shred-previous,
+// the file previously at path is moved aside first and shredded once write
+// succeeds, or put back if it fails, so a failed rotation never loses the
+// previous secret.
+func replaceFile(path string, write func() error) error {
+	if !*flagShredPrevious {
+		return write()
+	}
+	previous, err := genpass.SoftDelete(path)
+	if err != nil {
+		return err
+	}
+	if err := write(); err != nil {
+		if previous != "" {
+			os.Rename(previous, path)
+		}
+		return err
+	}
+	if previous == "" {
+		return nil
+	}
+	return genpass.Shred(previous)
+}
+
+// cmdShred overwrites files with random data and removes them, for secrets
+// written to files that are no longer needed.
+//
+//	genpass shred old-passwords.txt recovery.pdf
+func cmdShred(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: genpass shred <file>...")
+	}
+	failed := 0
+	for _, path := range args {
+		if err := genpass.Shred(path); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d file(s) could not be shredded", failed)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+var flagSplit = flag.String("split", "", "print the secret only as K/N Shamir shares, any K of which recover it with \"genpass combine\"")
+
+// parseSplit parses the K/N argument of --split.
+func parseSplit(s string) (k, n int, err error) {
+	ks, ns, ok := strings.Cut(s, "/")
+	k, kerr := strconv.Atoi(ks)
+	n, nerr := strconv.Atoi(ns)
+	if !ok || kerr != nil || nerr != nil {
+		return 0, 0, usagef("invalid --split %q, expected K/N such as 3/5", s)
+	}
+	return k, n, nil
+}
+
+// printShares prints the Shamir shares of secret requested with --split, one
+// per line in hex.
+func printShares(secret string) {
+	k, n, err := parseSplit(*flagSplit)
+	if err != nil {
+		fatal(err)
+	}
+	shares, err := genpass.Split([]byte(secret), k, n)
+	if err != nil {
+		fatal(err)
+	}
+	for _, share := range shares {
+		fmt.Println(hex.EncodeToString(share))
+	}
+}
+
+// cmdCombine recovers a secret from shares printed with --split, given as
+// arguments or on standard input, one per line.
+//
+//	genpass combine SHARE SHARE SHARE
+func cmdCombine(args []string) error {
+	lines := args
+	if len(lines) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	if len(lines) == 0 {
+		return usageError("usage: genpass combine <share>...")
+	}
+
+	shares := make([][]byte, len(lines))
+	for i, line := range lines {
+		share, err := hex.DecodeString(strings.ReplaceAll(line, " ", ""))
+		if err != nil {
+			return fmt.Errorf("share %d: %w", i+1, err)
+		}
+		shares[i] = share
+	}
+	secret, err := genpass.Combine(shares)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(secret))
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/calico32/genpass"
+)
+
+var flagStats = flag.Bool("stats", false, "print throughput, rejections by constraint, entropy, and wall time of batch runs to stderr")
+
+// batchStats collects the statistics printed with --stats from observer
+// events.
+type batchStats struct {
+	start        time.Time
+	entropyStart uint64
+
+	mu        sync.Mutex
+	generated int
+	bits      float64
+	rejected  map[genpass.RejectReason]int
+	missing   map[genpass.Class]int
+	// bytes is the size of the output, set when the batch finishes.
+	bytes int64
+}
+
+// startStats starts collecting statistics. The returned function stops
+// collecting.
+func startStats() (*batchStats, func()) {
+	s := &batchStats{
+		start:        time.Now(),
+		entropyStart: genpass.EntropyRead(),
+		rejected:     map[genpass.RejectReason]int{},
+		missing:      map[genpass.Class]int{},
+	}
+	stop := genpass.RegisterObserver(genpass.ObserverFuncs{
+		Generate: func(e genpass.GenerateEvent) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.generated++
+			s.bits += e.Entropy
+		},
+		Reject: func(e genpass.RejectEvent) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.rejected[e.Reason]++
+			for _, c := range e.Missing {
+				s.missing[c]++
+			}
+		},
+	})
+	return s, stop
+}
+
+// print writes the statistics to w. Rejection rates are relative to the
+// number of candidates, accepted or not.
+func (s *batchStats) print(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elapsed := time.Since(s.start)
+	secs := max(elapsed.Seconds(), 1e-9)
+
+	rejected := 0
+	for _, n := range s.rejected {
+		rejected += n
+	}
+	candidates := s.generated + rejected
+	rate := func(n int) string {
+		return fmt.Sprintf("%.2f%%", 100*float64(n)/float64(max(candidates, 1)))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Secrets:\t%d (%.0f/s, %s)\n", s.generated, float64(s.generated)/secs, formatThroughput(float64(s.bytes)/secs))
+	fmt.Fprintf(tw, "Candidates:\t%d (%s rejected)\n", candidates, rate(rejected))
+	for _, reason := range slices.Sorted(maps.Keys(s.rejected)) {
+		n := s.rejected[reason]
+		line := fmt.Sprintf("  %s:\t%d (%s)", reason, n, rate(n))
+		if reason == genpass.RejectMissingClass && len(s.missing) > 0 {
+			var parts []string
+			for _, c := range slices.Sorted(maps.Keys(s.missing)) {
+				parts = append(parts, fmt.Sprintf("%s %d", c, s.missing[c]))
+			}
+			line += ", lacking " + strings.Join(parts, ", ")
+		}
+		fmt.Fprintln(tw, line)
+	}
+	fmt.Fprintf(tw, "Entropy:\t%.0f bits in secrets, %d random bytes read\n", s.bits, genpass.EntropyRead()-s.entropyStart)
+	fmt.Fprintf(tw, "Time:\t%v\n", elapsed.Round(time.Millisecond))
+	return tw.Flush()
+}
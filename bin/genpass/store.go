@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+var flagStore = flag.String("store", "", "save the secret in the system credential store under this name instead of printing it")
+
+// storeSecret saves secret in the system credential store under the name given
+// with --store.
+func storeSecret(secret string) {
+	store, err := genpass.SystemStore()
+	if err == nil {
+		err = store.Set(*flagStore, []byte(secret))
+	}
+	if err != nil {
+		fatal(fmt.Errorf("failed to store secret: %w", err))
+	}
+	if !*flagQuiet {
+		fmt.Fprintf(os.Stderr, "Stored secret as %q\n", *flagStore)
+	}
+}
+
+// cmdGet prints a secret saved with --store.
+//
+//	genpass get NAME
+func cmdGet(args []string) error {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	noNewline := fs.Bool("n", false, "do not print a trailing newline")
+	del := fs.Bool("delete", false, "remove the secret instead of printing it")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return usageError("usage: genpass get [-n] [-delete] NAME")
+	}
+	name := fs.Arg(0)
+
+	store, err := genpass.SystemStore()
+	if err != nil {
+		return err
+	}
+	if *del {
+		return store.Delete(name)
+	}
+	secret, err := store.Get(name)
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(secret)
+	if !*noNewline {
+		fmt.Println()
+	}
+	return nil
+}
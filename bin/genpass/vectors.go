@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/calico32/genpass"
+)
+
+// cmdVectors prints the canonical test vectors for a seed as JSON, for
+// checking ports of genpass to other languages.
+//
+//	genpass vectors [-seed "genpass test vectors v1"] > vectors.json
+func cmdVectors(args []string) error {
+	fs := flag.NewFlagSet("vectors", flag.ExitOnError)
+	seed := fs.String("seed", genpass.DefaultVectorSeed, "seed the random inputs are derived from")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		return usageError("usage: genpass vectors [-seed seed]")
+	}
+
+	vectors, err := genpass.Vectors([]byte(*seed))
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(struct {
+		Seed    string           `json:"seed"`
+		Vectors []genpass.Vector `json:"vectors"`
+	}{*seed, vectors})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/calico32/genpass"
+)
+
+var flagTranscriptionCheck = flag.Bool("transcription-check", false, "append a 2-character checksum for verifying hand-copied values with verify-code")
+
+// cmdVerifyCode checks a value printed with --transcription-check. The value
+// is read from the arguments, or from stdin if there are none.
+//
+//	genpass verify-code [-print] VALUE
+func cmdVerifyCode(args []string) error {
+	fs := flag.NewFlagSet("verify-code", flag.ExitOnError)
+	show := fs.Bool("print", false, "print the value without its checksum")
+	fs.Parse(args)
+
+	value := strings.Join(fs.Args(), " ")
+	if fs.NArg() == 0 {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return err
+		}
+		value = line
+	}
+
+	value, err := genpass.VerifyChecksum(value)
+	if err != nil {
+		return err
+	}
+	if *show {
+		fmt.Println(value)
+	} else {
+		fmt.Fprintln(os.Stderr, "OK")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package genpass
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+)
+
+// BloomFilter is a probabilistic set of strings. It never reports a string
+// that was added as absent, but may report a string that wasn't added as
+// present with a configurable false positive rate.
+type BloomFilter struct {
+	bits []uint64
+	k    uint64
+}
+
+// NewBloomFilter creates a filter sized to hold n strings with a false
+// positive rate of at most p.
+func NewBloomFilter(n int, p float64) *BloomFilter {
+	n = max(n, 1)
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	return &BloomFilter{
+		bits: make([]uint64, (uint64(m)+63)/64),
+		k:    uint64(max(k, 1)),
+	}
+}
+
+// hashes returns two independent hashes of s, from which the k bit positions
+// are derived by double hashing.
+func (f *BloomFilter) hashes(s string) (uint64, uint64) {
+	h := fnv.New128a()
+	h.Write([]byte(s))
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:]) | 1
+}
+
+// Add adds s to the filter and reports whether it was possibly present
+// already.
+func (f *BloomFilter) Add(s string) bool {
+	h1, h2 := f.hashes(s)
+	m := uint64(len(f.bits)) * 64
+	present := true
+	for i := range f.k {
+		bit := (h1 + i*h2) % m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			present = false
+			f.bits[bit/64] |= 1 << (bit % 64)
+		}
+	}
+	return present
+}
+
+// Test reports whether s is possibly in the filter.
+func (f *BloomFilter) Test(s string) bool {
+	h1, h2 := f.hashes(s)
+	m := uint64(len(f.bits)) * 64
+	for i := range f.k {
+		bit := (h1 + i*h2) % m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 8, 8+8*len(f.bits))
+	binary.BigEndian.PutUint64(data, f.k)
+	for _, w := range f.bits {
+		data = binary.BigEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 || len(data)%8 != 0 {
+		return errors.New("genpass: invalid bloom filter data")
+	}
+	f.k = binary.BigEndian.Uint64(data)
+	f.bits = make([]uint64, len(data)/8-1)
+	for i := range f.bits {
+		f.bits[i] = binary.BigEndian.Uint64(data[8+8*i:])
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package genpass
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	babbleVowels     = "aeiouy"
+	babbleConsonants = "bcdfghklmnprstvzx"
+)
+
+// BubbleBabble is an [Encoder] and [Decoder] for the Bubble Babble binary data
+// encoding, which produces pronounceable, checksummed strings such as
+// "xesef-disof-gytuf-katof-movif-baxux". It is commonly used to display key
+// fingerprints for verification by humans.
+var BubbleBabble bubbleBabble
+
+type bubbleBabble struct{}
+
+func (bubbleBabble) Encode(src []byte) string {
+	var sb strings.Builder
+	sb.WriteByte('x')
+	seed := 1
+	rounds := len(src)/2 + 1
+	for i := range rounds {
+		if i+1 < rounds || len(src)%2 != 0 {
+			b1 := int(src[2*i])
+			sb.WriteByte(babbleVowels[((b1>>6)&3+seed)%6])
+			sb.WriteByte(babbleConsonants[(b1>>2)&15])
+			sb.WriteByte(babbleVowels[((b1&3)+seed/6)%6])
+			if i+1 < rounds {
+				b2 := int(src[2*i+1])
+				sb.WriteByte(babbleConsonants[(b2>>4)&15])
+				sb.WriteByte('-')
+				sb.WriteByte(babbleConsonants[b2&15])
+				seed = (seed*5 + b1*7 + b2) % 36
+			}
+		} else {
+			sb.WriteByte(babbleVowels[seed%6])
+			sb.WriteByte('x')
+			sb.WriteByte(babbleVowels[seed/6])
+		}
+	}
+	sb.WriteByte('x')
+	return sb.String()
+}
+
+var errInvalidBubbleBabble = errors.New("genpass: invalid bubble babble")
+
+func (bubbleBabble) Decode(s string) ([]byte, error) {
+	s = strings.ToLower(s)
+	if len(s) < 5 || s[0] != 'x' || s[len(s)-1] != 'x' {
+		return nil, errInvalidBubbleBabble
+	}
+	s = strings.ReplaceAll(s[1:len(s)-1], "-", "")
+	if len(s)%5 != 3 {
+		return nil, errInvalidBubbleBabble
+	}
+
+	idx := func(alphabet string, c byte) int {
+		return strings.IndexByte(alphabet, c)
+	}
+	// decodeByte reverses the vowel-consonant-vowel encoding of a byte.
+	decodeByte := func(t string, seed int) (int, bool) {
+		a1, c, a2 := idx(babbleVowels, t[0]), idx(babbleConsonants, t[1]), idx(babbleVowels, t[2])
+		if a1 < 0 || c < 0 || c > 15 || a2 < 0 {
+			return 0, false
+		}
+		hi := (a1 - seed%6 + 6) % 6
+		lo := (a2 - seed/6 + 6) % 6
+		if hi > 3 || lo > 3 {
+			return 0, false
+		}
+		return hi<<6 | c<<2 | lo, true
+	}
+
+	var dst []byte
+	seed := 1
+	for ; len(s) > 3; s = s[5:] {
+		b1, ok := decodeByte(s[:3], seed)
+		if !ok {
+			return nil, errInvalidBubbleBabble
+		}
+		c1, c2 := idx(babbleConsonants, s[3]), idx(babbleConsonants, s[4])
+		if c1 < 0 || c1 > 15 || c2 < 0 || c2 > 15 {
+			return nil, errInvalidBubbleBabble
+		}
+		b2 := c1<<4 | c2
+		dst = append(dst, byte(b1), byte(b2))
+		seed = (seed*5 + b1*7 + b2) % 36
+	}
+
+	if s[1] == 'x' {
+		if idx(babbleVowels, s[0]) != seed%6 || idx(babbleVowels, s[2]) != seed/6 {
+			return nil, errInvalidBubbleBabble
+		}
+		return dst, nil
+	}
+	b1, ok := decodeByte(s, seed)
+	if !ok {
+		return nil, errInvalidBubbleBabble
+	}
+	return append(dst, byte(b1)), nil
+}
+
+func init() {
+	RegisterEncoder("bubblebabble", BubbleBabble)
+}
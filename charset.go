@@ -0,0 +1,209 @@
+package genpass
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CharsetAmbiguous contains characters that are easily confused with each
+// other in many fonts.
+const CharsetAmbiguous = "01IOl|"
+
+// Charset is an immutable set of characters. Unlike plain strings, charsets
+// never contain duplicates, so combining overlapping sets doesn't bias
+// generation towards the shared characters.
+//
+// The zero value is the empty set.
+type Charset struct {
+	chars []rune // sorted, no duplicates
+}
+
+// NewCharset returns the set of characters in s.
+func NewCharset(s string) Charset {
+	chars := []rune(s)
+	slices.Sort(chars)
+	return Charset{slices.Compact(chars)}
+}
+
+// Len returns the number of characters in the set.
+func (c Charset) Len() int {
+	return len(c.chars)
+}
+
+// Contains reports whether r is in the set.
+func (c Charset) Contains(r rune) bool {
+	_, found := slices.BinarySearch(c.chars, r)
+	return found
+}
+
+// ContainsAny reports whether any character of s is in the set.
+func (c Charset) ContainsAny(s string) bool {
+	return strings.ContainsFunc(s, c.Contains)
+}
+
+// Union returns the set of characters in c or any of others.
+func (c Charset) Union(others ...Charset) Charset {
+	chars := slices.Clone(c.chars)
+	for _, o := range others {
+		chars = append(chars, o.chars...)
+	}
+	slices.Sort(chars)
+	return Charset{slices.Compact(chars)}
+}
+
+// Subtract returns the set of characters in c but not in any of others.
+func (c Charset) Subtract(others ...Charset) Charset {
+	var chars []rune
+outer:
+	for _, r := range c.chars {
+		for _, o := range others {
+			if o.Contains(r) {
+				continue outer
+			}
+		}
+		chars = append(chars, r)
+	}
+	return Charset{chars}
+}
+
+// Intersect returns the set of characters in both c and other.
+func (c Charset) Intersect(other Charset) Charset {
+	var chars []rune
+	for _, r := range c.chars {
+		if other.Contains(r) {
+			chars = append(chars, r)
+		}
+	}
+	return Charset{chars}
+}
+
+// Runes returns the characters in the set in ascending order.
+func (c Charset) Runes() []rune {
+	return slices.Clone(c.chars)
+}
+
+// String returns the characters in the set in ascending order. It can be
+// passed to [WithCharset] and the other functions that take a charset string.
+func (c Charset) String() string {
+	return string(c.chars)
+}
+
+var (
+	namedCharsetsMu sync.RWMutex
+	namedCharsets   = map[string]Charset{
+		"lower":     NewCharset(CharsetLower),
+		"upper":     NewCharset(CharsetUpper),
+		"alpha":     NewCharset(CharsetAlpha),
+		"num":       NewCharset(CharsetNum),
+		"digit":     NewCharset(CharsetNum),
+		"alnum":     NewCharset(CharsetAlphaNum),
+		"alphanum":  NewCharset(CharsetAlphaNum),
+		"hex":       NewCharset(CharsetHex),
+		"special":   NewCharset(CharsetSpecial),
+		"all":       NewCharset(CharsetAll),
+		"ambiguous": NewCharset(CharsetAmbiguous),
+	}
+)
+
+// RegisterCharset makes a charset available by name to [LookupCharset] and
+// [ParseCharset]. Names may contain letters, digits, and underscores.
+func RegisterCharset(name string, c Charset) {
+	namedCharsetsMu.Lock()
+	defer namedCharsetsMu.Unlock()
+	namedCharsets[strings.ToLower(name)] = c
+}
+
+// LookupCharset returns the charset registered under name. The built-in
+// names are lower, upper, alpha, num (or digit), alnum (or alphanum), hex,
+// special, all, and ambiguous.
+func LookupCharset(name string) (Charset, bool) {
+	namedCharsetsMu.RLock()
+	defer namedCharsetsMu.RUnlock()
+	c, ok := namedCharsets[strings.ToLower(name)]
+	return c, ok
+}
+
+// ParseCharset evaluates a charset expression such as "alpha+num-ambiguous".
+//
+// An expression is a sequence of terms joined by + (union), - (difference), or
+// & (intersection), evaluated from left to right. A term is either the name of
+// a charset (see [LookupCharset]) or a literal set of characters in square
+// brackets, e.g. "[!?#]". Inside brackets, a backslash escapes the next
+// character.
+func ParseCharset(expr string) (Charset, error) {
+	p := charsetParser{s: expr}
+	result, err := p.term()
+	if err != nil {
+		return Charset{}, err
+	}
+	for p.pos < len(p.s) {
+		op := p.s[p.pos]
+		p.pos++
+		t, err := p.term()
+		if err != nil {
+			return Charset{}, err
+		}
+		switch op {
+		case '+':
+			result = result.Union(t)
+		case '-':
+			result = result.Subtract(t)
+		case '&':
+			result = result.Intersect(t)
+		default:
+			return Charset{}, fmt.Errorf("genpass: unexpected %q at position %d in charset expression", op, p.pos-1)
+		}
+	}
+	return result, nil
+}
+
+type charsetParser struct {
+	s   string
+	pos int
+}
+
+func (p *charsetParser) term() (Charset, error) {
+	if p.pos >= len(p.s) {
+		return Charset{}, fmt.Errorf("genpass: unexpected end of charset expression %q", p.s)
+	}
+
+	if p.s[p.pos] == '[' {
+		var sb strings.Builder
+		for p.pos++; p.pos < len(p.s); {
+			r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+			p.pos += size
+			switch r {
+			case ']':
+				return NewCharset(sb.String()), nil
+			case '\\':
+				if p.pos < len(p.s) {
+					r, size = utf8.DecodeRuneInString(p.s[p.pos:])
+					p.pos += size
+				}
+			}
+			sb.WriteRune(r)
+		}
+		return Charset{}, fmt.Errorf("genpass: unterminated [ in charset expression %q", p.s)
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && isNameByte(p.s[p.pos]) {
+		p.pos++
+	}
+	name := p.s[start:p.pos]
+	if name == "" {
+		return Charset{}, fmt.Errorf("genpass: expected charset name at position %d in %q", start, p.s)
+	}
+	c, ok := LookupCharset(name)
+	if !ok {
+		return Charset{}, fmt.Errorf("genpass: unknown charset %q", name)
+	}
+	return c, nil
+}
+
+func isNameByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
@@ -0,0 +1,55 @@
+package genpass
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// ChecksumAlphabet is the alphabet of transcription checksums: Crockford's
+// base32, which leaves out I, L, O, and U to avoid confusion when reading
+// and writing by hand.
+const ChecksumAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ChecksumLen is the number of characters in a transcription checksum.
+const ChecksumLen = 2
+
+// ErrChecksum is returned by [VerifyChecksum] when a value doesn't match its
+// checksum.
+var ErrChecksum = errors.New("genpass: checksum mismatch")
+
+// Checksum returns a 2-character checksum of s for detecting transcription
+// errors. It is derived from a hash of s, so it catches about 1023 of 1024
+// errors of any kind, and reveals nothing about the structure of s beyond
+// 10 bits of information.
+func Checksum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	n := int(sum[0])<<2 | int(sum[1]>>6)
+	return string([]byte{ChecksumAlphabet[n>>5], ChecksumAlphabet[n&31]})
+}
+
+// AppendChecksum returns s followed by a space and its [Checksum].
+func AppendChecksum(s string) string {
+	return s + " " + Checksum(s)
+}
+
+// VerifyChecksum checks a value produced by [AppendChecksum] and returns the
+// value without the checksum. The checksum is not case-sensitive, and O, I,
+// and L are read as 0, 1, and 1.
+func VerifyChecksum(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= ChecksumLen {
+		return "", errors.New("genpass: value too short to contain a checksum")
+	}
+	split := len(s) - ChecksumLen
+	if !utf8.RuneStart(s[split]) || !utf8.RuneStart(s[split+1]) {
+		return "", ErrChecksum
+	}
+	value, check := strings.TrimSuffix(s[:split], " "), s[split:]
+	check = strings.NewReplacer("O", "0", "I", "1", "L", "1").Replace(strings.ToUpper(check))
+	if check != Checksum(value) {
+		return "", ErrChecksum
+	}
+	return value, nil
+}
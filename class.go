@@ -0,0 +1,97 @@
+package genpass
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Class is a class of characters commonly referenced by password rules.
+type Class int
+
+const (
+	ClassLower Class = iota
+	ClassUpper
+	ClassDigit
+	ClassSpecial
+)
+
+// Classes contains all character classes.
+var Classes = []Class{ClassLower, ClassUpper, ClassDigit, ClassSpecial}
+
+var classNames = [...]string{
+	ClassLower:   "lower",
+	ClassUpper:   "upper",
+	ClassDigit:   "digit",
+	ClassSpecial: "special",
+}
+
+var classCharsets = [...]string{
+	ClassLower:   CharsetLower,
+	ClassUpper:   CharsetUpper,
+	ClassDigit:   CharsetNum,
+	ClassSpecial: CharsetSpecial,
+}
+
+var classSets = func() []Charset {
+	sets := make([]Charset, len(classCharsets))
+	for i, cs := range classCharsets {
+		sets[i] = NewCharset(cs)
+	}
+	return sets
+}()
+
+// String returns the name of the class, e.g. "lower".
+func (c Class) String() string {
+	return classNames[c]
+}
+
+// Charset returns the characters in the class.
+func (c Class) Charset() string {
+	return classCharsets[c]
+}
+
+// Set returns the characters in the class as a [Charset].
+func (c Class) Set() Charset {
+	return classSets[c]
+}
+
+// ParseClass parses a class name as returned by [Class.String]. "number" is
+// accepted as an alias of "digit".
+func ParseClass(s string) (Class, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "number" {
+		return ClassDigit, nil
+	}
+	for c, name := range classNames {
+		if s == name {
+			return Class(c), nil
+		}
+	}
+	return 0, fmt.Errorf("genpass: unknown character class %q", s)
+}
+
+// ParseClasses parses a comma-separated list of class names.
+func ParseClasses(s string) ([]Class, error) {
+	var classes []Class
+	for _, name := range strings.Split(s, ",") {
+		c, err := ParseClass(name)
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, c)
+	}
+	return uniqueClasses(classes), nil
+}
+
+// uniqueClasses returns classes without repeated classes, in the order they
+// first appear.
+func uniqueClasses(classes []Class) []Class {
+	var unique []Class
+	for _, c := range classes {
+		if !slices.Contains(unique, c) {
+			unique = append(unique, c)
+		}
+	}
+	return unique
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"slices"
 	"strings"
 )
 
@@ -53,34 +54,96 @@ func GetCollisionSecondsFromLength(charsetLen int, passwordLen int) *big.Int {
 
 // FormatDuration formats a number of seconds into a human-readable string using
 // the largest unit of time that is less than the duration, e.g. "2 million
-// years".
+// years". The output uses the current locale (see [SetLocale]); use
+// [Locale.FormatDuration] to format in another locale.
 //
-// If the duration is greater than 999 trillion years, it returns
-// "an eternity", and if the duration is less than a second, it returns
-// "less than a second".
+// If the duration is at least 999 googol years, it returns "an eternity", and
+// if the duration is less than a second, it returns "less than a second".
 func FormatDuration(seconds *big.Int) string {
+	return CurrentLocale().FormatDuration(seconds)
+}
+
+// FormatDurationOpts controls the precision of [FormatDurationWith]. The zero
+// value formats durations like [FormatDuration].
+type FormatDurationOpts struct {
+	// MaxUnits is the largest number of units in the output, e.g. 3 for "3
+	// years, 2 days, 5 hours". Units that are zero are left out but still
+	// count towards the limit, so the output is precise to the same unit
+	// either way. Durations of a thousand years or more are always formatted
+	// with a single unit; use Decimals for precision instead. Zero means 1.
+	MaxUnits int
+	// Decimals is the number of decimal places of the last unit, e.g. 1 for
+	// "2.4 million years". Extra digits are truncated, not rounded.
+	Decimals int
+	// Months adds months, a twelfth of a year, between days and years, e.g.
+	// "3 years, 2 months, 5 days".
+	Months bool
+}
+
+// FormatDurationWith is like [FormatDuration] but with the precision set by
+// opts, since truncating to a single whole unit can understate a duration by
+// nearly half.
+func FormatDurationWith(seconds *big.Int, opts FormatDurationOpts) string {
+	return CurrentLocale().FormatDurationWith(seconds, opts)
+}
+
+// FormatDuration is like the package-level [FormatDuration], but uses the unit
+// names of l regardless of the current locale, so that a server can format
+// durations for each client in its own language.
+func (l *Locale) FormatDuration(seconds *big.Int) string {
+	return l.FormatDurationWith(seconds, FormatDurationOpts{})
+}
+
+// FormatDurationWith is like the package-level [FormatDurationWith], but uses
+// the unit names of l regardless of the current locale.
+func (l *Locale) FormatDurationWith(seconds *big.Int, opts FormatDurationOpts) string {
 	limitSeconds := new(big.Int).Mul(log10years(100), big.NewInt(999))
 
 	if seconds.Cmp(limitSeconds) >= 0 {
-		return "an eternity"
+		return l.T(MsgEternity)
+	}
+
+	units := units
+	if opts.Months {
+		year := slices.IndexFunc(units, func(u durationUnit) bool { return u.msg == MsgUnitYear })
+		units = slices.Insert(slices.Clone(units), year, durationUnit{MsgUnitMonth, new(big.Int).Div(oneYear, big.NewInt(12))})
 	}
 
-	for i := len(units) - 1; i >= 0; i-- {
-		if seconds.Cmp(units[i].value) >= 0 {
-			unitName := units[i].name
-			unitSeconds := units[i].value
-			result := new(big.Int).Div(seconds, unitSeconds)
-			if !strings.Contains(unitName, " ") {
-				// we need to pluralize the unit if the number is 1
-				if result.Cmp(big.NewInt(1)) == 0 {
-					unitName += "s"
-				}
+	largest := len(units) - 1
+	for largest >= 0 && seconds.Cmp(units[largest].value) < 0 {
+		largest--
+	}
+	if largest < 0 {
+		return l.T(MsgLessThanSecond)
+	}
+
+	maxUnits := max(opts.MaxUnits, 1)
+	if units[largest].value.Cmp(oneYear) > 0 {
+		maxUnits = 1
+	}
+
+	var parts []string
+	rem := new(big.Int).Set(seconds)
+	for i := largest; i >= 0 && i > largest-maxUnits; i-- {
+		unit := units[i]
+		n, r := new(big.Int).QuoRem(rem, unit.value, new(big.Int))
+		rem = r
+		if opts.Decimals > 0 && (i == 0 || i == largest-maxUnits+1) {
+			// scale the remainder to the requested number of digits
+			scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(opts.Decimals)), nil)
+			frac := new(big.Int).Quo(scale.Mul(scale, r), unit.value)
+			if n.Sign() > 0 || frac.Sign() > 0 {
+				digits := fmt.Sprintf("%0*s", opts.Decimals, frac.String())
+				parts = append(parts, l.decimal(unit.msg, n, digits))
 			}
-			return fmt.Sprintf("%s %s", result.String(), unitName)
+			break
+		}
+		if n.Sign() > 0 {
+			parts = append(parts, l.N(unit.msg, n))
 		}
 	}
 
-	return "less than a second"
+	return strings.Join(parts, l.T(MsgUnitSeparator))
 }
 
 var oneYear = big.NewInt(31536000)
@@ -90,47 +153,49 @@ func log10years(pow int64) *big.Int {
 	return i.Mul(i, oneYear)
 }
 
-var units = []struct {
-	name  string
+type durationUnit struct {
+	msg   Message
 	value *big.Int
-}{
-	{"second", big.NewInt(1)},
-	{"minute", big.NewInt(60)},
-	{"hour", big.NewInt(3600)},
-	{"day", big.NewInt(86400)},
-	{"year", oneYear},
-	{"thousand years", log10years(3)},
-	{"million years", log10years(6)},
-	{"billion years", log10years(9)},
-	{"trillion years", log10years(12)},
-	{"quadrillion years", log10years(15)},
-	{"quintillion years", log10years(18)},
-	{"sextillion years", log10years(21)},
-	{"septillion years", log10years(24)},
-	{"octillion years", log10years(27)},
-	{"nonillion years", log10years(30)},
-	{"decillion years", log10years(33)},
-	{"undecillion years", log10years(36)},
-	{"duodecillion years", log10years(39)},
-	{"tredecillion years", log10years(42)},
-	{"quattuordecillion years", log10years(45)},
-	{"quindecillion years", log10years(48)},
-	{"sexdecillion years", log10years(51)},
-	{"septendecillion years", log10years(54)},
-	{"octodecillion years", log10years(57)},
-	{"novemdecillion years", log10years(60)},
-	{"vigintillion years", log10years(63)},
-	{"unvigintillion years", log10years(66)},
-	{"duovigintillion years", log10years(69)},
-	{"trevigintillion years", log10years(72)},
-	{"quattuorvigintillion years", log10years(75)},
-	{"quinvigintillion years", log10years(78)},
-	{"sexvigintillion years", log10years(81)},
-	{"septenvigintillion years", log10years(84)},
-	{"octovigintillion years", log10years(87)},
-	{"novemvigintillion years", log10years(90)},
-	{"trigintillion years", log10years(93)},
-	{"untrigintillion years", log10years(96)},
-	{"duotrigintillion years", log10years(99)},
-	{"googol years", log10years(100)},
+}
+
+var units = []durationUnit{
+	{MsgUnitSecond, big.NewInt(1)},
+	{MsgUnitMinute, big.NewInt(60)},
+	{MsgUnitHour, big.NewInt(3600)},
+	{MsgUnitDay, big.NewInt(86400)},
+	{MsgUnitYear, oneYear},
+	{MsgUnitThousandYears, log10years(3)},
+	{MsgUnitMillionYears, log10years(6)},
+	{MsgUnitBillionYears, log10years(9)},
+	{MsgUnitTrillionYears, log10years(12)},
+	{MsgUnitQuadrillionYears, log10years(15)},
+	{MsgUnitQuintillionYears, log10years(18)},
+	{MsgUnitSextillionYears, log10years(21)},
+	{MsgUnitSeptillionYears, log10years(24)},
+	{MsgUnitOctillionYears, log10years(27)},
+	{MsgUnitNonillionYears, log10years(30)},
+	{MsgUnitDecillionYears, log10years(33)},
+	{MsgUnitUndecillionYears, log10years(36)},
+	{MsgUnitDuodecillionYears, log10years(39)},
+	{MsgUnitTredecillionYears, log10years(42)},
+	{MsgUnitQuattuordecillionYears, log10years(45)},
+	{MsgUnitQuindecillionYears, log10years(48)},
+	{MsgUnitSexdecillionYears, log10years(51)},
+	{MsgUnitSeptendecillionYears, log10years(54)},
+	{MsgUnitOctodecillionYears, log10years(57)},
+	{MsgUnitNovemdecillionYears, log10years(60)},
+	{MsgUnitVigintillionYears, log10years(63)},
+	{MsgUnitUnvigintillionYears, log10years(66)},
+	{MsgUnitDuovigintillionYears, log10years(69)},
+	{MsgUnitTrevigintillionYears, log10years(72)},
+	{MsgUnitQuattuorvigintillionYears, log10years(75)},
+	{MsgUnitQuinvigintillionYears, log10years(78)},
+	{MsgUnitSexvigintillionYears, log10years(81)},
+	{MsgUnitSeptenvigintillionYears, log10years(84)},
+	{MsgUnitOctovigintillionYears, log10years(87)},
+	{MsgUnitNovemvigintillionYears, log10years(90)},
+	{MsgUnitTrigintillionYears, log10years(93)},
+	{MsgUnitUntrigintillionYears, log10years(96)},
+	{MsgUnitDuotrigintillionYears, log10years(99)},
+	{MsgUnitGoogolYears, log10years(100)},
 }
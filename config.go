@@ -0,0 +1,107 @@
+package genpass
+
+import "errors"
+
+// Config is a serializable generator configuration, suitable for storing in
+// files or sending over the network. The zero value generates 16-character
+// passwords from [CharsetAll].
+type Config struct {
+	// Charset is a charset expression understood by [ParseCharset], e.g.
+	// "alpha+num". It is ignored for passphrases.
+	Charset string `json:"charset,omitempty"`
+	// Length is the number of characters in generated passwords.
+	Length int `json:"length,omitempty"`
+	// Require lists the names of classes that must appear in passwords.
+	Require []string `json:"require,omitempty"`
+	// Group and GroupSeparator split passwords into groups, see
+	// [WithGrouping].
+	Group          int    `json:"group,omitempty"`
+	GroupSeparator string `json:"groupSeparator,omitempty"`
+
+	// Words switches to passphrase generation with this many words from
+	// [WordlistEFF].
+	Words int `json:"words,omitempty"`
+	// Separator is placed between passphrase words. The default is a space.
+	Separator *string `json:"separator,omitempty"`
+	// Capitalize, Leet, and AddDigit enable the [Capitalize], [LeetRandom],
+	// and [InsertDigit] passphrase transforms. Leet is the substitution
+	// probability.
+	Capitalize bool    `json:"capitalize,omitempty"`
+	Leet       float64 `json:"leet,omitempty"`
+	AddDigit   bool    `json:"addDigit,omitempty"`
+
+	// MinEntropy is passed to [WithMinEntropy].
+	MinEntropy float64 `json:"minEntropy,omitempty"`
+}
+
+// Options converts the configuration to generator options.
+func (c Config) Options() ([]Option, error) {
+	var opts []Option
+
+	if c.Words > 0 {
+		opts = append(opts, WithWords(WordlistEFF, c.Words))
+		if c.Separator != nil {
+			opts = append(opts, WithSeparator(*c.Separator))
+		}
+		var transforms []Transform
+		if c.Capitalize {
+			transforms = append(transforms, Capitalize)
+		}
+		if c.Leet > 0 {
+			transforms = append(transforms, LeetRandom(c.Leet))
+		}
+		if c.AddDigit {
+			transforms = append(transforms, InsertDigit)
+		}
+		opts = append(opts, WithTransforms(transforms...))
+	} else {
+		if c.Charset != "" {
+			cs, err := ParseCharset(c.Charset)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithCharset(cs.String()))
+		}
+		if c.Length < 0 {
+			return nil, errors.New("genpass: negative length")
+		}
+		if c.Length > 0 {
+			opts = append(opts, WithLength(c.Length))
+		}
+		var required []Class
+		for _, name := range c.Require {
+			class, err := ParseClass(name)
+			if err != nil {
+				return nil, err
+			}
+			required = append(required, class)
+		}
+		opts = append(opts, WithRequiredClasses(required...))
+		if c.Group > 0 {
+			sep := c.GroupSeparator
+			if sep == "" {
+				sep = "-"
+			}
+			opts = append(opts, WithGrouping(c.Group, sep))
+		}
+	}
+
+	if c.MinEntropy > 0 {
+		opts = append(opts, WithMinEntropy(c.MinEntropy))
+	}
+	return opts, nil
+}
+
+// Generator creates a [Generator] from the configuration and checks that it
+// can generate passwords.
+func (c Config) Generator() (*Generator, error) {
+	opts, err := c.Options()
+	if err != nil {
+		return nil, err
+	}
+	g := NewGenerator(opts...)
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
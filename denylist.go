@@ -0,0 +1,88 @@
+package genpass
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minDenylistLen is the length of the shortest denylist entry that is used.
+// Shorter entries would rule out a large share of all passwords.
+const minDenylistLen = 3
+
+// WithDenylist rejects passwords and passphrases that contain any of words,
+// such as dictionary words, the account name, or banned company names. The
+// comparison ignores case and leet-speak substitutions, so "adm1n" and "@DMIN"
+// both match "admin". Entries shorter than 3 characters are ignored.
+//
+// Rejected candidates are generated again, like those missing a required
+// class. The entropy reported for the generator does not account for the
+// denylist, which only removes a negligible part of the keyspace for
+// passwords of a useful length.
+func WithDenylist(words []string) Option {
+	return func(g *Generator) {
+		d := &denylist{words: map[string]struct{}{}}
+		for _, w := range words {
+			w = normalizeLeet(w)
+			n := len([]rune(w))
+			if n < minDenylistLen {
+				continue
+			}
+			d.words[w] = struct{}{}
+			d.maxLen = max(d.maxLen, n)
+		}
+		if len(d.words) == 0 {
+			d = nil
+		}
+		g.denylist = d
+	}
+}
+
+// denylist is a set of normalized words that must not appear in generated
+// secrets.
+type denylist struct {
+	words  map[string]struct{}
+	maxLen int
+}
+
+// contains reports whether s contains a word of the denylist. A nil denylist
+// contains nothing.
+func (d *denylist) contains(s string) bool {
+	if d == nil {
+		return false
+	}
+	// look up every substring of a plausible length rather than searching
+	// for every word, so large dictionaries stay cheap
+	chars := []rune(normalizeLeet(s))
+	for i := range chars {
+		for n := minDenylistLen; n <= d.maxLen && i+n <= len(chars); n++ {
+			if _, ok := d.words[string(chars[i:i+n])]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unleet maps leet-speak characters, and letters they are easily confused
+// with, to a single letter.
+var unleet = map[rune]rune{
+	'4': 'a', '@': 'a',
+	'8': 'b',
+	'3': 'e',
+	'9': 'g',
+	'1': 'i', '!': 'i', '|': 'i', 'l': 'i',
+	'0': 'o',
+	'5': 's', '$': 's',
+	'7': 't', '+': 't',
+}
+
+// normalizeLeet lowercases s and undoes leet-speak substitutions.
+func normalizeLeet(s string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if u, ok := unleet[r]; ok {
+			return u
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,151 @@
+package genpass
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// EntropyTerm is one component of the entropy of a configuration, as listed by
+// [Generator.EntropyDerivation].
+type EntropyTerm struct {
+	// Source names the part of the configuration, like "words", "charset",
+	// "positions 1-4", or "transform insert-digit".
+	Source string
+	// Formula shows how the bits were computed, like "16 × log2(72)".
+	Formula string
+	// Bits is the entropy the component adds. It is negative for
+	// constraints that exclude some outputs, like required classes.
+	Bits float64
+}
+
+// EntropyDerivation breaks [Generator.Entropy] down into the contribution of
+// each component of the configuration, so that the figure can be checked by
+// hand. The bits of the terms add up to the entropy.
+//
+// The entropy is that of the whole generation process: duplicate words and
+// characters, weights, transforms, and rejected candidates are all accounted
+// for, instead of assuming every output is drawn uniformly from the full
+// charset or wordlist. Rejections by [WithDenylist] aren't, since they remove
+// a negligible fraction of the outputs of any configuration that passes
+// [Generator.Validate].
+func (g *Generator) EntropyDerivation() []EntropyTerm {
+	return g.entropyTerms(true)
+}
+
+// entropyTerms returns the terms of the generator's entropy, leaving out the
+// formulas unless explain is set, since Entropy is computed for every
+// generated secret.
+func (g *Generator) entropyTerms(explain bool) []EntropyTerm {
+	var terms []EntropyTerm
+	add := func(source string, bits float64, formula func() string) {
+		t := EntropyTerm{Source: source, Bits: bits}
+		if explain {
+			t.Formula = formula()
+		}
+		terms = append(terms, t)
+	}
+
+	if g.Passphrase() {
+		if g.weights != nil {
+			per := ShannonEntropy(g.weights)
+			add("words", per*float64(g.words), func() string {
+				return fmt.Sprintf("%d × %.2f bits (Shannon entropy of %d weighted words)", g.words, per, len(g.wordlist))
+			})
+		} else {
+			add("words", g.wordBits*float64(g.words), func() string {
+				return choiceFormula(g.words, g.wordlist)
+			})
+		}
+		for _, t := range g.transforms {
+			add("transform "+transformName(t), g.transformEntropy(t), func() string {
+				if d, ok := t.(transformDescriber); ok {
+					return d.describe(g.wordlist, g.weights, g.words)
+				}
+				return "added by the transform's random choices"
+			})
+		}
+		return terms
+	}
+
+	naive := 0.0
+	if g.positions != nil {
+		// group runs of positions with the same charset
+		for start := 0; start < len(g.positions); {
+			end := start + 1
+			for end < len(g.positions) && g.positions[end].String() == g.positions[start].String() {
+				end++
+			}
+			n, size := end-start, g.positions[start].Len()
+			bits := float64(n) * math.Log2(float64(size))
+			naive += bits
+			source := fmt.Sprintf("position %d", start+1)
+			if n > 1 {
+				source = fmt.Sprintf("positions %d-%d", start+1, end)
+			}
+			add(source, bits, func() string {
+				return choiceFormula(n, []rune(g.positions[start].String()))
+			})
+			start = end
+		}
+	} else {
+		naive = math.Log2(float64(len(g.charset))) * float64(g.length)
+		add("charset", naive, func() string {
+			f := choiceFormula(g.length, g.charset)
+			if g.duplicates > 0 {
+				f += fmt.Sprintf(" (%d duplicate characters ignored)", g.duplicates)
+			}
+			return f
+		})
+	}
+
+	if len(g.required) > 0 {
+		exact := log2Int(g.Possibilities())
+		add("required classes", exact-naive, func() string {
+			names := make([]string, len(g.required))
+			for i, c := range g.required {
+				names[i] = c.String()
+			}
+			return fmt.Sprintf("rejects %.2f%% of candidates for lacking %s", (1-g.AcceptanceRate())*100, strings.Join(names, ", "))
+		})
+	}
+	return terms
+}
+
+// transformEntropy returns the entropy added by a transform, taking the
+// weights of the wordlist into account if the transform supports it.
+func (g *Generator) transformEntropy(t Transform) float64 {
+	if wt, ok := t.(WeightedTransform); ok && g.weights != nil {
+		return wt.WeightedEntropy(g.wordlist, g.weights, g.words)
+	}
+	return t.Entropy(g.wordlist, g.words)
+}
+
+// transformName returns a name for t in entropy derivations.
+func transformName(t Transform) string {
+	if s, ok := t.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", t)
+}
+
+// transformDescriber is implemented by the built-in transforms to explain
+// their entropy in derivations.
+type transformDescriber interface {
+	describe(wordlist []string, weights []int, count int) string
+}
+
+// choiceFormula explains the entropy of count uniform choices from items.
+func choiceFormula[T comparable](count int, items []T) string {
+	distinct := map[T]bool{}
+	for _, item := range items {
+		distinct[item] = true
+	}
+	if len(distinct) != len(items) {
+		return fmt.Sprintf("%d × %.2f bits (%d distinct of %d, duplicates are likelier)", count, multisetEntropy(items), len(distinct), len(items))
+	}
+	if count == 1 {
+		return fmt.Sprintf("log2(%d)", len(items))
+	}
+	return fmt.Sprintf("%d × log2(%d)", count, len(items))
+}
@@ -0,0 +1,109 @@
+package genpass
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// NamedSecret is a secret together with where it should end up.
+type NamedSecret struct {
+	// Name identifies the secret in the target system, e.g. the name of a
+	// Kubernetes Secret.
+	Name string
+	// Key is the key the value is stored under within the secret.
+	Key string
+	// Value is the secret itself.
+	Value string
+}
+
+// Emitter writes a secret in a format ready to be consumed by another system,
+// such as a Kubernetes manifest.
+type Emitter interface {
+	Emit(w io.Writer, s NamedSecret) error
+}
+
+// EmitterFunc adapts a function to an [Emitter].
+type EmitterFunc func(w io.Writer, s NamedSecret) error
+
+func (f EmitterFunc) Emit(w io.Writer, s NamedSecret) error { return f(w, s) }
+
+var (
+	emittersMu sync.RWMutex
+	emitters   = map[string]Emitter{}
+)
+
+// RegisterEmitter makes an emitter available by name to [LookupEmitter].
+// Registering an emitter with an existing name replaces it.
+func RegisterEmitter(name string, e Emitter) {
+	emittersMu.Lock()
+	defer emittersMu.Unlock()
+	emitters[strings.ToLower(name)] = e
+}
+
+// LookupEmitter returns the emitter registered under name. Names are not
+// case-sensitive.
+func LookupEmitter(name string) (Emitter, bool) {
+	emittersMu.RLock()
+	defer emittersMu.RUnlock()
+	e, ok := emitters[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return observedEmitter{strings.ToLower(name), e}, true
+}
+
+// Emitters returns the names of all registered emitters in sorted order.
+func Emitters() []string {
+	emittersMu.RLock()
+	defer emittersMu.RUnlock()
+	names := make([]string, 0, len(emitters))
+	for name := range emitters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+var (
+	// k8sNameRE matches an RFC 1123 subdomain, the format of Secret names.
+	k8sNameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	k8sKeyRE  = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+)
+
+// KubernetesSecret emits an Opaque Kubernetes Secret manifest that can be
+// applied with kubectl apply -f. It is registered as "k8s-secret".
+var KubernetesSecret Emitter = EmitterFunc(func(w io.Writer, s NamedSecret) error {
+	if len(s.Name) > 253 || !k8sNameRE.MatchString(s.Name) {
+		return fmt.Errorf("genpass: invalid Kubernetes secret name %q", s.Name)
+	}
+	if len(s.Key) > 253 || !k8sKeyRE.MatchString(s.Key) {
+		return fmt.Errorf("genpass: invalid Kubernetes secret key %q", s.Key)
+	}
+	_, err := fmt.Fprintf(w, "apiVersion: v1\nkind: Secret\nmetadata:\n  name: %s\ntype: Opaque\ndata:\n  %s: %s\n",
+		s.Name, s.Key, base64.StdEncoding.EncodeToString([]byte(s.Value)))
+	return err
+})
+
+// VaultKV emits a JSON object that can be written to a HashiCorp Vault KV
+// secrets engine with vault kv put <path> @file.json. The name is not part of
+// the output. It is registered as "vault-kv".
+var VaultKV Emitter = EmitterFunc(func(w io.Writer, s NamedSecret) error {
+	if s.Key == "" {
+		return errors.New("genpass: empty Vault key")
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]string{s.Key: s.Value})
+})
+
+func init() {
+	RegisterEmitter("k8s-secret", KubernetesSecret)
+	RegisterEmitter("vault-kv", VaultKV)
+}
@@ -0,0 +1,132 @@
+package genpass
+
+import (
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Encoder turns random bytes into text.
+type Encoder interface {
+	Encode(src []byte) string
+}
+
+// Decoder is implemented by encoders that can reverse their encoding.
+type Decoder interface {
+	Decode(s string) ([]byte, error)
+}
+
+// EncoderFunc adapts a function to an [Encoder].
+type EncoderFunc func(src []byte) string
+
+func (f EncoderFunc) Encode(src []byte) string { return f(src) }
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]Encoder{}
+)
+
+// RegisterEncoder makes an encoder available by name to [LookupEncoder] and
+// [EncodeBytes]. Registering an encoder with an existing name replaces it.
+func RegisterEncoder(name string, enc Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[strings.ToLower(name)] = enc
+}
+
+// LookupEncoder returns the encoder registered under name. Names are not
+// case-sensitive.
+func LookupEncoder(name string) (Encoder, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	enc, ok := encoders[strings.ToLower(name)]
+	return enc, ok
+}
+
+// Encoders returns the names of all registered encoders in sorted order.
+func Encoders() []string {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GenerateBytes returns n cryptographically secure random bytes.
+func GenerateBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(entropySource{}, b); err != nil {
+		return nil, entropyError(err)
+	}
+	return b, nil
+}
+
+// EncodeBytes encodes src with the encoder registered under name.
+func EncodeBytes(name string, src []byte) (string, error) {
+	enc, ok := LookupEncoder(name)
+	if !ok {
+		return "", fmt.Errorf("genpass: unknown encoding %q", name)
+	}
+	return enc.Encode(src), nil
+}
+
+// DecodeBytes decodes s with the encoder registered under name. The encoder
+// must implement [Decoder].
+func DecodeBytes(name string, s string) ([]byte, error) {
+	enc, ok := LookupEncoder(name)
+	if !ok {
+		return nil, fmt.Errorf("genpass: unknown encoding %q", name)
+	}
+	dec, ok := enc.(Decoder)
+	if !ok {
+		return nil, fmt.Errorf("genpass: encoding %q does not support decoding", name)
+	}
+	return dec.Decode(s)
+}
+
+type stdEncoding struct {
+	encode func([]byte) string
+	decode func(string) ([]byte, error)
+}
+
+func (e stdEncoding) Encode(src []byte) string        { return e.encode(src) }
+func (e stdEncoding) Decode(s string) ([]byte, error) { return e.decode(s) }
+
+type ascii85Encoding struct{}
+
+func (ascii85Encoding) Encode(src []byte) string {
+	dst := make([]byte, ascii85.MaxEncodedLen(len(src)))
+	n := ascii85.Encode(dst, src)
+	return string(dst[:n])
+}
+
+func (ascii85Encoding) Decode(s string) ([]byte, error) {
+	dst := make([]byte, len(s))
+	n, _, err := ascii85.Decode(dst, []byte(s), true)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+func init() {
+	RegisterEncoder("hex", stdEncoding{hex.EncodeToString, hex.DecodeString})
+	RegisterEncoder("base32", stdEncoding{
+		base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString,
+		func(s string) ([]byte, error) {
+			return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(s))
+		},
+	})
+	RegisterEncoder("base64", stdEncoding{base64.StdEncoding.EncodeToString, base64.StdEncoding.DecodeString})
+	RegisterEncoder("base64url", stdEncoding{base64.RawURLEncoding.EncodeToString, base64.RawURLEncoding.DecodeString})
+	RegisterEncoder("base85", ascii85Encoding{})
+}
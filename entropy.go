@@ -0,0 +1,62 @@
+package genpass
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+// entropyReader reads random bytes from the current entropy source in batches
+// and turns them into uniformly distributed indexes using rejection sampling.
+type entropyReader struct {
+	r   *bufio.Reader
+	buf [4]byte
+}
+
+const (
+	// entropyBatchSize is the batch size used for large outputs.
+	entropyBatchSize = 64 * 1024
+	// entropyPoolBatchSize is the batch size of pooled readers, which are
+	// used for short passwords.
+	entropyPoolBatchSize = 512
+)
+
+func newEntropyReader(size int) *entropyReader {
+	return &entropyReader{r: bufio.NewReaderSize(entropySource{}, size)}
+}
+
+// entropyPool holds entropy readers for reuse across calls, so that generating
+// many short passwords doesn't read a fresh batch of random bytes each time.
+var entropyPool = sync.Pool{
+	New: func() any { return newEntropyReader(entropyPoolBatchSize) },
+}
+
+// Intn returns a uniformly random integer in [0, n). n must be in the range
+// (0, 2^32].
+func (e *entropyReader) Intn(n int) (int, error) {
+	if n <= 256 {
+		// reject values in the final partial block to avoid modulo bias
+		limit := 256 - 256%n
+		for {
+			b, err := e.r.ReadByte()
+			if err != nil {
+				return 0, entropyError(err)
+			}
+			if int(b) < limit {
+				return int(b) % n, nil
+			}
+		}
+	}
+
+	limit := uint64(1<<32) - uint64(1<<32)%uint64(n)
+	for {
+		if _, err := io.ReadFull(e.r, e.buf[:]); err != nil {
+			return 0, entropyError(err)
+		}
+		v := uint64(binary.LittleEndian.Uint32(e.buf[:]))
+		if v < limit {
+			return int(v % uint64(n)), nil
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package genpass
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// EntropySource is a source of cryptographically secure random bytes that
+// secrets can be generated from.
+type EntropySource struct {
+	// Name identifies the source in [LookupEntropySource] and
+	// [UseEntropySource].
+	Name string
+	// Description is a short human-readable description of the source.
+	Description string
+	// Open returns a reader of random bytes, safe for concurrent use. It
+	// fails if the source isn't available on the current machine.
+	Open func() (io.Reader, error)
+}
+
+// Built-in entropy sources.
+const (
+	// EntropySystem reads from the operating system's random number
+	// generator through crypto/rand. It is the default.
+	EntropySystem = "system"
+	// EntropyChaCha20 is a ChaCha20 deterministic random bit generator seeded
+	// from the system, which avoids a system call for every batch of random
+	// bytes.
+	EntropyChaCha20 = "chacha20"
+	// EntropyHardware reads from the hardware random number generator exposed
+	// by the kernel, if there is one.
+	EntropyHardware = "hardware"
+)
+
+// hardwareRNGPath is the device of the kernel's hardware random number
+// generator on Linux.
+const hardwareRNGPath = "/dev/hwrng"
+
+var (
+	entropySourcesMu sync.RWMutex
+	entropySources   = map[string]EntropySource{
+		EntropySystem: {
+			Name:        EntropySystem,
+			Description: "operating system random number generator",
+			Open:        func() (io.Reader, error) { return rand.Reader, nil },
+		},
+		EntropyChaCha20: {
+			Name:        EntropyChaCha20,
+			Description: "ChaCha20 DRBG seeded from the system",
+			Open:        func() (io.Reader, error) { return newChaChaDRBG() },
+		},
+		EntropyHardware: {
+			Name:        EntropyHardware,
+			Description: "hardware random number generator (" + hardwareRNGPath + ")",
+			Open: func() (io.Reader, error) {
+				return os.Open(hardwareRNGPath)
+			},
+		},
+	}
+)
+
+// RegisterEntropySource makes an entropy source available by name to
+// [LookupEntropySource] and [UseEntropySource]. Registering a source with an
+// existing name replaces it.
+func RegisterEntropySource(src EntropySource) {
+	entropySourcesMu.Lock()
+	defer entropySourcesMu.Unlock()
+	src.Name = strings.ToLower(src.Name)
+	entropySources[src.Name] = src
+}
+
+// LookupEntropySource returns the entropy source registered under name. Names
+// are not case-sensitive.
+func LookupEntropySource(name string) (EntropySource, bool) {
+	entropySourcesMu.RLock()
+	defer entropySourcesMu.RUnlock()
+	src, ok := entropySources[strings.ToLower(name)]
+	return src, ok
+}
+
+// EntropySources returns the names of all registered entropy sources in
+// sorted order.
+func EntropySources() []string {
+	entropySourcesMu.RLock()
+	defer entropySourcesMu.RUnlock()
+	names := make([]string, 0, len(entropySources))
+	for name := range entropySources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// currentEntropy is the reader secrets are generated from, and the name of
+// its source.
+var currentEntropy atomic.Pointer[openEntropySource]
+
+type openEntropySource struct {
+	name string
+	r    io.Reader
+}
+
+// UseEntropySource opens the entropy source registered under name and
+// generates all subsequent secrets from it. It should be called before
+// secrets are generated, since readers that already hold a batch of random
+// bytes use it up first.
+func UseEntropySource(name string) error {
+	src, ok := LookupEntropySource(name)
+	if !ok {
+		return fmt.Errorf("genpass: unknown entropy source %q", name)
+	}
+	r, err := src.Open()
+	if err != nil {
+		return entropyError(err)
+	}
+	currentEntropy.Store(&openEntropySource{name: src.Name, r: r})
+	return nil
+}
+
+// CurrentEntropySource returns the name of the entropy source secrets are
+// generated from.
+func CurrentEntropySource() string {
+	if cur := currentEntropy.Load(); cur != nil {
+		return cur.name
+	}
+	return EntropySystem
+}
+
+// entropy returns the reader of the current entropy source.
+func entropy() io.Reader {
+	if cur := currentEntropy.Load(); cur != nil {
+		return cur.r
+	}
+	return rand.Reader
+}
+
+// entropySource reads from the current entropy source at the time of each
+// read, and counts the bytes read for [EntropyRead].
+type entropySource struct{}
+
+func (entropySource) Read(p []byte) (int, error) {
+	n, err := entropy().Read(p)
+	entropyBytesRead.Add(uint64(n))
+	return n, err
+}
+
+var entropyBytesRead atomic.Uint64
+
+// EntropyRead returns the number of random bytes the package has read from
+// entropy sources so far. Random bytes are read in batches, so it includes
+// bytes that are buffered but not used yet.
+func EntropyRead() uint64 {
+	return entropyBytesRead.Load()
+}
+
+// chachaReseedInterval is the number of bytes a ChaCha20 DRBG produces before
+// it is seeded again from the system.
+const chachaReseedInterval = 1 << 30
+
+// chachaDRBG generates random bytes from the ChaCha20 keystream of a key read
+// from the system. After each read, the key is replaced with the next bytes of
+// the keystream (fast key erasure), so the state no longer determines output
+// that was already returned, and the DRBG is periodically seeded again from the
+// system, so a compromised state only predicts a limited amount of output.
+type chachaDRBG struct {
+	mu     sync.Mutex
+	cipher *chacha20.Cipher
+	output int
+}
+
+func newChaChaDRBG() (*chachaDRBG, error) {
+	d := &chachaDRBG{}
+	if err := d.reseed(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *chachaDRBG) reseed() error {
+	var seed [chacha20.KeySize + chacha20.NonceSize]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return err
+	}
+	c, err := chacha20.NewUnauthenticatedCipher(seed[:chacha20.KeySize], seed[chacha20.KeySize:])
+	if err != nil {
+		return err
+	}
+	d.cipher, d.output = c, 0
+	return nil
+}
+
+func (d *chachaDRBG) Read(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.output+len(p) > chachaReseedInterval {
+		if err := d.reseed(); err != nil {
+			return 0, err
+		}
+	}
+	clear(p)
+	d.cipher.XORKeyStream(p, p)
+	d.output += len(p)
+	if err := d.rekey(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// rekey replaces the key with the next bytes of the keystream.
+func (d *chachaDRBG) rekey() error {
+	var key [chacha20.KeySize]byte
+	var nonce [chacha20.NonceSize]byte
+	d.cipher.XORKeyStream(key[:], key[:])
+	c, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	clear(key[:])
+	if err != nil {
+		return err
+	}
+	d.cipher = c
+	return nil
+}
@@ -0,0 +1,145 @@
+package genpass
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// keystream returns the first n bytes of the ChaCha20 keystream of key with
+// an all-zero nonce.
+func keystream(t *testing.T, key []byte, n int) []byte {
+	t.Helper()
+	c, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]byte, n)
+	c.XORKeyStream(out, out)
+	return out
+}
+
+func TestChaChaDRBGKeyErasure(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, chacha20.KeySize)
+	c, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &chachaDRBG{cipher: c}
+
+	// each read returns the start of the keystream of the current key, and
+	// the key is then replaced by the keystream bytes that follow it
+	for range 3 {
+		stream := keystream(t, key, 16+chacha20.KeySize)
+		got := make([]byte, 16)
+		if _, err := d.Read(got); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, stream[:16]) {
+			t.Fatalf("read %x, want %x", got, stream[:16])
+		}
+		if d.cipher == c {
+			t.Fatal("cipher was not replaced after a read")
+		}
+		c, key = d.cipher, stream[16:]
+	}
+}
+
+func TestChaChaDRBGReseed(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, chacha20.KeySize)
+	c, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &chachaDRBG{cipher: c, output: chachaReseedInterval - 8}
+	got := make([]byte, 16)
+	if _, err := d.Read(got); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, keystream(t, key, 16)) {
+		t.Error("DRBG was not seeded again after the reseed interval")
+	}
+	if d.output != len(got) {
+		t.Errorf("output = %d after reseeding, want %d", d.output, len(got))
+	}
+}
+
+// fixedReader reads an endless repetition of its byte.
+type fixedReader byte
+
+func (r fixedReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+// useEntropySource selects the entropy source registered under name for the
+// rest of the test.
+func useEntropySource(t *testing.T, name string) error {
+	prev := currentEntropy.Load()
+	t.Cleanup(func() { currentEntropy.Store(prev) })
+	return UseEntropySource(name)
+}
+
+func TestUseEntropySource(t *testing.T) {
+	RegisterEntropySource(EntropySource{
+		Name: "Fixed",
+		Open: func() (io.Reader, error) { return fixedReader(0x42), nil },
+	})
+	t.Cleanup(func() {
+		entropySourcesMu.Lock()
+		delete(entropySources, "fixed")
+		entropySourcesMu.Unlock()
+	})
+
+	if err := useEntropySource(t, "FIXED"); err != nil {
+		t.Fatal(err)
+	}
+	if name := CurrentEntropySource(); name != "fixed" {
+		t.Errorf("CurrentEntropySource() = %q, want %q", name, "fixed")
+	}
+	before := EntropyRead()
+	b, err := GenerateBytes(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, bytes.Repeat([]byte{0x42}, 8)) {
+		t.Errorf("GenerateBytes read %x from the selected source", b)
+	}
+	if n := EntropyRead() - before; n != 8 {
+		t.Errorf("EntropyRead increased by %d, want 8", n)
+	}
+
+	if err := useEntropySource(t, EntropyChaCha20); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := entropy().(*chachaDRBG); !ok {
+		t.Errorf("entropy() is %T after selecting %s", entropy(), EntropyChaCha20)
+	}
+
+	if err := useEntropySource(t, "nonexistent"); err == nil {
+		t.Error("selecting an unknown entropy source succeeded")
+	}
+	if name := CurrentEntropySource(); name != EntropyChaCha20 {
+		t.Errorf("CurrentEntropySource() = %q after a failed selection, want %q", name, EntropyChaCha20)
+	}
+}
+
+func TestUseEntropySourceOpenError(t *testing.T) {
+	RegisterEntropySource(EntropySource{
+		Name: "broken",
+		Open: func() (io.Reader, error) { return nil, errors.New("unavailable") },
+	})
+	t.Cleanup(func() {
+		entropySourcesMu.Lock()
+		delete(entropySources, "broken")
+		entropySourcesMu.Unlock()
+	})
+	if err := useEntropySource(t, "broken"); !errors.Is(err, ErrEntropySource) {
+		t.Errorf("UseEntropySource of a broken source = %v, want %v", err, ErrEntropySource)
+	}
+}
@@ -0,0 +1,66 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by the package wrap one of these when their cause falls
+// into a category a caller may want to handle, so that it can be detected
+// with [errors.Is]. Errors that fall into none of them are caused by invalid
+// arguments or configuration.
+var (
+	// ErrPolicyViolation means a secret does not satisfy a [Policy]. The
+	// violations are available from a [*PolicyError].
+	ErrPolicyViolation = errors.New("genpass: policy violation")
+	// ErrSinkUnavailable means secrets could not be written to an output,
+	// such as a batch file or a [SecretStore]. The output is identified by a
+	// [*SinkError].
+	ErrSinkUnavailable = errors.New("genpass: sink unavailable")
+	// ErrEntropySource means the entropy source secrets are generated from,
+	// by default the system's secure random number generator, failed.
+	ErrEntropySource = errors.New("genpass: entropy source failed")
+	// ErrKeyspaceExhausted means no acceptable secret was found after many
+	// attempts, because the requirements, denylist, or uniqueness constraint
+	// leave too few of the possible secrets.
+	ErrKeyspaceExhausted = errors.New("genpass: keyspace exhausted")
+)
+
+// PolicyError is returned by [Policy.Check] for a secret that violates a
+// policy. It matches [ErrPolicyViolation].
+type PolicyError struct {
+	Policy     Policy
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return fmt.Sprintf("genpass: does not satisfy the %s policy: %s", e.Policy.Description, strings.Join(msgs, "; "))
+}
+
+func (e *PolicyError) Is(target error) bool { return target == ErrPolicyViolation }
+
+// SinkError records a failure to write secrets to an output. It matches
+// [ErrSinkUnavailable] and unwraps to the underlying error.
+type SinkError struct {
+	// Sink identifies the output, as in [SinkWriteEvent].
+	Sink string
+	Err  error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("genpass: writing to %s: %s", e.Sink, strings.TrimPrefix(e.Err.Error(), "genpass: "))
+}
+
+func (e *SinkError) Is(target error) bool { return target == ErrSinkUnavailable }
+
+func (e *SinkError) Unwrap() error { return e.Err }
+
+// entropyError wraps an error from the random number generator.
+func entropyError(err error) error {
+	return fmt.Errorf("%w: %w", ErrEntropySource, err)
+}
@@ -0,0 +1,338 @@
+package genpass
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FuzzConfig is a generator configuration encoded at the start of the inputs
+// of [Fuzzable]. Fuzzers mutate the encoded form; build seed inputs with
+// [FuzzConfig.Encode] or take them from [FuzzCorpus].
+type FuzzConfig struct {
+	// Classes are the classes of the charset. If empty, all classes are used.
+	Classes []Class
+	// Required are the classes passwords must contain.
+	Required []Class
+	// Length is the password length, from 1 to 64.
+	Length int
+	// Policy is the name of a registered policy whose options replace
+	// Classes, Required, and Length, or empty for none.
+	Policy string
+	// Passphrase generates passphrases of Words words from the EFF wordlist,
+	// from 1 to 16, with the given transforms.
+	Passphrase  bool
+	Words       int
+	Capitalize  bool
+	Leet        bool
+	InsertDigit bool
+	// Denylist rejects secrets containing one of [FuzzDenylist].
+	Denylist bool
+	// Group groups passwords in fours.
+	Group bool
+}
+
+// FuzzDenylist is the denylist used by [Fuzzable] inputs with
+// [FuzzConfig.Denylist] set. Its words are short so that they are actually
+// hit with small charsets.
+var FuzzDenylist = []string{"abc", "123", "pass", "qwe", "!!!"}
+
+// fuzzHeaderSize is the number of bytes of an encoded [FuzzConfig].
+const fuzzHeaderSize = 6
+
+// Flags in the fourth byte of an encoded FuzzConfig.
+const (
+	fuzzPassphrase = 1 << iota
+	fuzzCapitalize
+	fuzzLeet
+	fuzzInsertDigit
+	fuzzDenylist
+	fuzzGroup
+)
+
+// DecodeFuzzConfig decodes the configuration at the start of data and returns
+// it with the remaining bytes, which [Fuzzable] uses as the source of
+// randomness. Any data decodes to a configuration; missing bytes are zero.
+func DecodeFuzzConfig(data []byte) (FuzzConfig, []byte) {
+	var h [fuzzHeaderSize]byte
+	n := copy(h[:], data)
+	rest := data[n:]
+
+	var c FuzzConfig
+	for _, class := range Classes {
+		if h[0]&(1<<class) != 0 {
+			c.Classes = append(c.Classes, class)
+		}
+		if h[1]&(1<<class) != 0 {
+			c.Required = append(c.Required, class)
+		}
+	}
+	c.Length = 1 + int(h[2])%64
+	flags := h[3]
+	c.Passphrase = flags&fuzzPassphrase != 0
+	c.Capitalize = flags&fuzzCapitalize != 0
+	c.Leet = flags&fuzzLeet != 0
+	c.InsertDigit = flags&fuzzInsertDigit != 0
+	c.Denylist = flags&fuzzDenylist != 0
+	c.Group = flags&fuzzGroup != 0
+	if policies := Policies(); h[4] != 0 && len(policies) > 0 {
+		c.Policy = policies[int(h[4]-1)%len(policies)]
+	}
+	c.Words = 1 + int(h[5])%16
+	return c, rest
+}
+
+// Encode returns a [Fuzzable] input with configuration c, followed by random
+// as the source of randomness.
+func (c FuzzConfig) Encode(random []byte) []byte {
+	var h [fuzzHeaderSize]byte
+	for _, class := range c.Classes {
+		h[0] |= 1 << class
+	}
+	for _, class := range c.Required {
+		h[1] |= 1 << class
+	}
+	h[2] = byte((max(c.Length, 1) - 1) % 64)
+	for _, f := range []struct {
+		set  bool
+		flag byte
+	}{
+		{c.Passphrase, fuzzPassphrase},
+		{c.Capitalize, fuzzCapitalize},
+		{c.Leet, fuzzLeet},
+		{c.InsertDigit, fuzzInsertDigit},
+		{c.Denylist, fuzzDenylist},
+		{c.Group, fuzzGroup},
+	} {
+		if f.set {
+			h[3] |= f.flag
+		}
+	}
+	if i := slices.Index(Policies(), strings.ToLower(c.Policy)); i >= 0 {
+		h[4] = byte(i + 1)
+	}
+	h[5] = byte((max(c.Words, 1) - 1) % 16)
+	return append(h[:], random...)
+}
+
+// Options returns the generator options for c.
+func (c FuzzConfig) Options() []Option {
+	var opts []Option
+	if c.Passphrase {
+		var transforms []Transform
+		if c.Capitalize {
+			transforms = append(transforms, Capitalize)
+		}
+		if c.Leet {
+			transforms = append(transforms, LeetRandom(0.5))
+		}
+		if c.InsertDigit {
+			transforms = append(transforms, InsertDigit)
+		}
+		opts = append(opts, WithWords(WordlistEFF, c.Words), WithSeparator(" "), WithTransforms(transforms...))
+	} else if p, ok := LookupPolicy(c.Policy); ok {
+		opts = append(opts, p.Options()...)
+	} else {
+		var set Charset
+		for _, class := range c.Classes {
+			set = set.Union(class.Set())
+		}
+		if set.Len() == 0 {
+			set = NewCharset(CharsetAll)
+		}
+		opts = append(opts, WithCharset(set.String()), WithLength(c.Length), WithRequiredClasses(c.Required...))
+	}
+	if c.Denylist {
+		opts = append(opts, WithDenylist(FuzzDenylist))
+	}
+	if c.Group && !c.Passphrase {
+		opts = append(opts, WithGrouping(4, " "))
+	}
+	return opts
+}
+
+// FuzzCorpus returns seed inputs for [Fuzzable] covering every class, every
+// registered policy, and passphrases with each transform.
+func FuzzCorpus() [][]byte {
+	random := make([]byte, 256)
+	for i := range random {
+		random[i] = byte(i * 167)
+	}
+	configs := []FuzzConfig{
+		{Length: 16},
+		{Length: 64, Required: Classes, Group: true},
+		{Length: 3, Classes: []Class{ClassDigit}, Denylist: true},
+		{Passphrase: true, Words: 6},
+		{Passphrase: true, Words: 4, Capitalize: true, Leet: true, InsertDigit: true, Denylist: true},
+	}
+	for _, class := range Classes {
+		configs = append(configs, FuzzConfig{Length: 8, Classes: []Class{class}, Required: []Class{class}})
+	}
+	for _, name := range Policies() {
+		configs = append(configs, FuzzConfig{Policy: name})
+	}
+	corpus := make([][]byte, len(configs))
+	for i, c := range configs {
+		corpus[i] = c.Encode(random)
+	}
+	return corpus
+}
+
+// Fuzzable is an entry point for fuzzing the constraint logic of generators,
+// with the signature go-fuzz expects. It decodes a [FuzzConfig] from data,
+// generates a secret using the rest of data as the only source of randomness,
+// and panics if the secret violates the configuration: a wrong length,
+// characters outside the charset, missing required classes, denylisted words,
+// or policy violations. It returns 1 if a secret was generated and 0 if the
+// configuration is invalid or data ran out of randomness.
+//
+// With native Go fuzzing, wrap it in a fuzz target:
+//
+//	func FuzzGenpass(f *testing.F) {
+//		for _, seed := range genpass.FuzzCorpus() {
+//			f.Add(seed)
+//		}
+//		f.Fuzz(func(t *testing.T, data []byte) { genpass.Fuzzable(data) })
+//	}
+func Fuzzable(data []byte) int {
+	c, random := DecodeFuzzConfig(data)
+	g := NewGenerator(c.Options()...)
+	s, err := g.GenerateFrom(NewByteRand(random))
+	if err != nil {
+		return 0
+	}
+	if err := checkFuzzOutput(c, g, s); err != nil {
+		panic(fmt.Sprintf("genpass: %v: config %+v, secret %q", err, c, s))
+	}
+	return 1
+}
+
+// checkFuzzOutput checks a secret generated from c without relying on the
+// generator's own checks.
+func checkFuzzOutput(c FuzzConfig, g *Generator, s string) error {
+	if e := g.Entropy(); math.IsNaN(e) || math.IsInf(e, 0) || e < 0 {
+		return fmt.Errorf("invalid entropy %v", e)
+	}
+	if c.Denylist {
+		normalized := normalizeLeet(s)
+		for _, w := range FuzzDenylist {
+			if strings.Contains(normalized, normalizeLeet(w)) {
+				return fmt.Errorf("contains denylisted %q", w)
+			}
+		}
+	}
+
+	if c.Passphrase {
+		words := strings.Split(s, " ")
+		if len(words) != c.Words {
+			return fmt.Errorf("%d words instead of %d", len(words), c.Words)
+		}
+		if !c.Capitalize && !c.Leet && !c.InsertDigit {
+			for _, w := range words {
+				if !effWords()[w] {
+					return fmt.Errorf("word %q is not in the wordlist", w)
+				}
+			}
+		}
+		return nil
+	}
+
+	password := s
+	if c.Group {
+		password = strings.ReplaceAll(s, " ", "")
+	}
+	p, hasPolicy := LookupPolicy(c.Policy)
+	length, set, required := c.Length, NewCharset(CharsetAll), c.Required
+	if hasPolicy {
+		length, required = p.Length(), p.RequiredClasses()
+	} else if len(c.Classes) > 0 {
+		set = Charset{}
+		for _, class := range c.Classes {
+			set = set.Union(class.Set())
+		}
+	}
+	if n := utf8.RuneCountInString(password); n != length {
+		return fmt.Errorf("%d characters instead of %d", n, length)
+	}
+	for _, r := range password {
+		if !set.Contains(r) {
+			return fmt.Errorf("character %q is not in the charset", r)
+		}
+	}
+	for _, class := range required {
+		if !class.Set().ContainsAny(password) {
+			return fmt.Errorf("missing required class %s", class)
+		}
+	}
+	if hasPolicy {
+		for _, v := range p.Validate(password) {
+			// blocklisted passwords are allowed, see Policy.Options
+			if v.Code != ViolationBlocklisted {
+				return fmt.Errorf("violates the %s policy: %s", p.Name, v.Message)
+			}
+		}
+	}
+	return nil
+}
+
+// effWords is the set of words of [WordlistEFF].
+var effWords = sync.OnceValue(func() map[string]bool {
+	words := make(map[string]bool, len(WordlistEFF))
+	for _, w := range WordlistEFF {
+		words[w] = true
+	}
+	return words
+})
+
+// byteRand is a [Rand] that draws from a fixed sequence of bytes.
+type byteRand struct {
+	data []byte
+}
+
+// NewByteRand returns a [Rand] that uses data as its only source of
+// randomness, so that the same data always produces the same choices. It
+// returns an error once data is used up. It is meant for fuzzing and tests;
+// secrets generated from it are only as random as data.
+func NewByteRand(data []byte) Rand {
+	return &byteRand{data: data}
+}
+
+func (b *byteRand) Intn(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("genpass: invalid argument to Intn: %d", n)
+	}
+	// like entropyReader, reject values in the final partial block to avoid
+	// modulo bias
+	size := 1
+	for size < 4 && 1<<(8*size) < n {
+		size++
+	}
+	limit := uint64(1) << (8 * size)
+	limit -= limit % uint64(n)
+	for {
+		if len(b.data) < size {
+			return 0, entropyError(io.ErrUnexpectedEOF)
+		}
+		var v uint64
+		for _, c := range b.data[:size] {
+			v = v<<8 | uint64(c)
+		}
+		b.data = b.data[size:]
+		if v < limit {
+			return int(v % uint64(n)), nil
+		}
+	}
+}
+
+// GenerateFrom is like [Generator.Generate], but makes its random choices
+// with r instead of the current entropy source.
+func (g *Generator) GenerateFrom(r Rand) (string, error) {
+	if err := g.Validate(); err != nil {
+		return "", err
+	}
+	return g.generate(r)
+}
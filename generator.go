@@ -0,0 +1,542 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Generator generates passwords or passphrases according to a set of options.
+// The zero value is not usable; create one with [NewGenerator].
+type Generator struct {
+	charset    []rune
+	duplicates int
+	length     int
+	positions  []Charset
+
+	wordlist   []string
+	weights    []int
+	cumulative []int
+	words      int
+	wordBits   float64
+	separator  string
+	transforms []Transform
+
+	groupSize int
+	groupSep  string
+
+	required []Class
+	specials Charset
+	denylist *denylist
+
+	minEntropy float64
+
+	policy string
+	audit  *AuditLog
+
+	// validate checks the configuration once; see [Generator.Validate].
+	validate func() error
+}
+
+// Option configures a [Generator].
+type Option func(*Generator)
+
+// NewGenerator creates a Generator with the given options. By default, it
+// generates 16-character passwords from [CharsetAll].
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{length: 16, separator: " "}
+	WithCharset(CharsetAll)(g)
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.Passphrase() && len(g.wordlist) > 0 {
+		// the wordlist may contain duplicates, which make some words more
+		// likely than others
+		g.wordBits = wordlistEntropy(g.wordlist)
+	}
+	g.validate = sync.OnceValue(g.check)
+	return g
+}
+
+// WithCharset sets the charset used to generate passwords.
+func WithCharset(charset string) Option {
+	return func(g *Generator) {
+		chars := []rune(charset)
+		slices.Sort(chars)
+		g.charset = slices.Compact(chars)
+		g.duplicates = len(chars) - len(g.charset)
+	}
+}
+
+// WithLength sets the number of characters in generated passwords.
+func WithLength(length int) Option {
+	return func(g *Generator) {
+		g.length = length
+	}
+}
+
+// WithPositions draws each character of generated passwords from its own
+// charset: the first from positions[0], the second from positions[1], and so
+// on. The password length is len(positions), and [WithCharset] and
+// [WithLength] are ignored. For example, two uppercase letters, six characters
+// from any class, and two digits:
+//
+//	upper, _ := LookupCharset("upper")
+//	all, _ := LookupCharset("all")
+//	digit, _ := LookupCharset("digit")
+//	positions := slices.Concat(
+//		slices.Repeat([]Charset{upper}, 2),
+//		slices.Repeat([]Charset{all}, 6),
+//		slices.Repeat([]Charset{digit}, 2),
+//	)
+//	g := NewGenerator(WithPositions(positions...))
+func WithPositions(positions ...Charset) Option {
+	return func(g *Generator) {
+		g.positions = positions
+	}
+}
+
+// WithWords makes the generator produce passphrases of count words drawn from
+// wordlist instead of passwords drawn from a charset.
+func WithWords(wordlist []string, count int) Option {
+	return func(g *Generator) {
+		g.wordlist = wordlist
+		g.words = count
+	}
+}
+
+// WithWeights makes the generator choose passphrase words with probability
+// proportional to their weights instead of uniformly, so that more common,
+// more memorable words come up more often. weights[i] is the weight of the
+// i-th word of the wordlist given to [WithWords]; see [LoadWeightedWordlist].
+//
+// The entropy of a weighted passphrase is the Shannon entropy of the weight
+// distribution times the number of words. It is always lower than for the
+// same wordlist sampled uniformly, and an attacker who guesses common words
+// first does better than the Shannon entropy suggests, so use a few more
+// words than you would otherwise.
+func WithWeights(weights []int) Option {
+	return func(g *Generator) {
+		g.weights = weights
+		g.cumulative = make([]int, len(weights))
+		total := 0
+		for i, w := range weights {
+			total += w
+			g.cumulative[i] = total
+		}
+	}
+}
+
+// WithSeparator sets the string placed between the words of a passphrase. The
+// default is a single space.
+func WithSeparator(sep string) Option {
+	return func(g *Generator) {
+		g.separator = sep
+	}
+}
+
+// WithTransforms sets the transforms applied, in order, to the words of a
+// passphrase. Transforms have no effect when generating from a charset.
+func WithTransforms(transforms ...Transform) Option {
+	return func(g *Generator) {
+		g.transforms = transforms
+	}
+}
+
+// WithGrouping splits generated passwords into groups of size characters
+// joined by sep, e.g. "xxxx-xxxx-xxxx-xxxx". The separators become part of the
+// password but do not count towards its length or entropy. Grouping has no
+// effect on passphrases.
+//
+// To group a password for display only, use [Group] instead.
+func WithGrouping(size int, sep string) Option {
+	return func(g *Generator) {
+		g.groupSize = size
+		g.groupSep = sep
+	}
+}
+
+// WithRequiredClasses requires generated passwords to contain at least one
+// character from each of the given classes. Passwords that don't are rejected
+// and generated again, so the result is uniformly distributed over all
+// passwords that satisfy the requirement. Repeating a class has no further
+// effect. Required classes have no effect on passphrases.
+func WithRequiredClasses(classes ...Class) Option {
+	return func(g *Generator) {
+		g.required = uniqueClasses(classes)
+	}
+}
+
+// WithSpecials sets the characters that count as [ClassSpecial] when the
+// class is required, for sites that accept a different set of special
+// characters than [CharsetSpecial].
+func WithSpecials(chars string) Option {
+	return func(g *Generator) {
+		g.specials = NewCharset(chars)
+	}
+}
+
+// WithMinEntropy makes [Generator.Generate] fail with an [*EntropyError] if
+// the generator is configured to produce passwords with less than bits of
+// entropy.
+func WithMinEntropy(bits float64) Option {
+	return func(g *Generator) {
+		g.minEntropy = bits
+	}
+}
+
+// EntropyError is returned when a generator's configuration cannot produce
+// passwords with the entropy required by [WithMinEntropy].
+type EntropyError struct {
+	// Entropy is the entropy of the configuration, in bits.
+	Entropy float64
+	// Min is the required entropy, in bits.
+	Min float64
+}
+
+func (e *EntropyError) Error() string {
+	return fmt.Sprintf("genpass: configuration provides %.2f bits of entropy, less than the required %.2f bits", e.Entropy, e.Min)
+}
+
+// ErrUnsatisfiable is returned when no password can satisfy the configured
+// requirements, e.g. because the password is shorter than the number of
+// required classes.
+var ErrUnsatisfiable = errors.New("genpass: requirements cannot be satisfied")
+
+// maxAttempts is the maximum number of candidates generated before giving up on
+// satisfying the requirements.
+const maxAttempts = 10000
+
+// Passphrase reports whether the generator produces word-based output.
+func (g *Generator) Passphrase() bool {
+	return g.wordlist != nil
+}
+
+// Validate checks that the generator's configuration can produce passwords,
+// returning the error [Generator.Generate] would return without generating
+// anything. The configuration is only checked once; later calls return the
+// same result.
+func (g *Generator) Validate() error {
+	return g.validate()
+}
+
+func (g *Generator) check() error {
+	if g.Passphrase() {
+		if len(g.wordlist) == 0 {
+			return errors.New("genpass: empty wordlist")
+		}
+		if g.words <= 0 {
+			return errors.New("genpass: number of words must be positive")
+		}
+		if g.weights != nil {
+			if len(g.weights) != len(g.wordlist) {
+				return fmt.Errorf("genpass: %d weights for %d words", len(g.weights), len(g.wordlist))
+			}
+			prev := 0
+			for i, c := range g.cumulative {
+				if c <= prev {
+					return fmt.Errorf("genpass: weight of %q must be positive and the total must fit in an int", g.wordlist[i])
+				}
+				prev = c
+			}
+		}
+	} else {
+		if g.length < 0 && g.positions == nil {
+			return errors.New("genpass: negative length")
+		}
+		if g.positions != nil {
+			for i, cs := range g.positions {
+				if cs.Len() == 0 {
+					return fmt.Errorf("genpass: empty charset at position %d", i+1)
+				}
+			}
+		} else if len(g.charset) == 0 {
+			return errors.New("genpass: empty charset")
+		}
+		// without required classes, any nonempty charset can produce
+		// passwords, so the count, which grows with the length, isn't needed
+		if len(g.required) > 0 && g.Possibilities().Sign() == 0 {
+			return ErrUnsatisfiable
+		}
+	}
+
+	if e := g.Entropy(); e < g.minEntropy {
+		return &EntropyError{Entropy: e, Min: g.minEntropy}
+	}
+	return nil
+}
+
+// Generate generates a password or passphrase.
+func (g *Generator) Generate() (string, error) {
+	if err := g.Validate(); err != nil {
+		return "", err
+	}
+
+	entropy := entropyPool.Get().(*entropyReader)
+	defer entropyPool.Put(entropy)
+	return g.generate(entropy)
+}
+
+// generate generates a password or passphrase with randomness from entropy,
+// without validating the configuration.
+func (g *Generator) generate(entropy Rand) (string, error) {
+	if g.Passphrase() {
+		words := make([]string, g.words)
+		for range maxAttempts {
+			for i := range words {
+				j, err := g.wordIndex(entropy)
+				if err != nil {
+					return "", err
+				}
+				words[i] = g.wordlist[j]
+			}
+			for _, t := range g.transforms {
+				if err := t.Apply(words, entropy); err != nil {
+					return "", err
+				}
+			}
+			passphrase := strings.Join(words, g.separator)
+			if g.denylist.contains(passphrase) {
+				notify(func(o Observer) { o.OnReject(RejectEvent{Reason: RejectDenylisted}) })
+				continue
+			}
+			g.notifyGenerate(passphrase)
+			if err := g.record(passphrase); err != nil {
+				return "", err
+			}
+			return passphrase, nil
+		}
+		return "", fmt.Errorf("%w: no passphrase avoided the denylist after %d attempts", ErrKeyspaceExhausted, maxAttempts)
+	}
+
+	password := make([]rune, g.passwordLength())
+	for range maxAttempts {
+		for i := range password {
+			charset := g.charsetAt(i)
+			j, err := entropy.Intn(len(charset))
+			if err != nil {
+				return "", err
+			}
+			password[i] = charset[j]
+		}
+		if !g.satisfiesRequired(password) {
+			if observing() {
+				e := RejectEvent{Reason: RejectMissingClass, Missing: g.missingClasses(password)}
+				notify(func(o Observer) { o.OnReject(e) })
+			}
+			continue
+		}
+		if g.denylist.contains(string(password)) {
+			notify(func(o Observer) { o.OnReject(RejectEvent{Reason: RejectDenylisted}) })
+			continue
+		}
+		s := Group(string(password), g.groupSize, g.groupSep)
+		g.notifyGenerate(s)
+		if err := g.record(s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+	return "", fmt.Errorf("%w: no password satisfied the requirements after %d attempts", ErrKeyspaceExhausted, maxAttempts)
+}
+
+// notifyGenerate reports a generated secret to observers.
+func (g *Generator) notifyGenerate(s string) {
+	if !observing() {
+		return
+	}
+	e := GenerateEvent{Passphrase: g.Passphrase(), Length: utf8.RuneCountInString(s), Entropy: g.Entropy()}
+	notify(func(o Observer) { o.OnGenerate(e) })
+}
+
+// wordIndex chooses the index of a passphrase word, taking weights into
+// account.
+func (g *Generator) wordIndex(rand Rand) (int, error) {
+	if g.weights == nil {
+		return rand.Intn(len(g.wordlist))
+	}
+	n, err := rand.Intn(g.cumulative[len(g.cumulative)-1])
+	if err != nil {
+		return 0, err
+	}
+	i, _ := slices.BinarySearch(g.cumulative, n+1)
+	return i, nil
+}
+
+// passwordLength returns the number of characters in generated passwords.
+func (g *Generator) passwordLength() int {
+	if g.positions != nil {
+		return len(g.positions)
+	}
+	return g.length
+}
+
+// charsetAt returns the characters allowed at position i.
+func (g *Generator) charsetAt(i int) []rune {
+	if g.positions != nil {
+		return g.positions[i].chars
+	}
+	return g.charset
+}
+
+func (g *Generator) satisfiesRequired(password []rune) bool {
+	for _, c := range g.required {
+		if !slices.ContainsFunc(password, g.classSet(c).Contains) {
+			return false
+		}
+	}
+	return true
+}
+
+// classSet returns the characters of class c, taking [WithSpecials] into
+// account.
+func (g *Generator) classSet(c Class) Charset {
+	if c == ClassSpecial && g.specials.Len() > 0 {
+		return g.specials
+	}
+	return c.Set()
+}
+
+// missingClasses returns the required classes password contains no
+// characters from.
+func (g *Generator) missingClasses(password []rune) []Class {
+	var missing []Class
+	for _, c := range g.required {
+		if !slices.ContainsFunc(password, g.classSet(c).Contains) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// Entropy returns the entropy, in bits, of the passwords produced by the
+// generator, including any entropy added by transforms.
+func (g *Generator) Entropy() float64 {
+	e := 0.0
+	for _, t := range g.entropyTerms(false) {
+		e += t.Bits
+	}
+	return e
+}
+
+// AcceptanceRate returns the fraction of random candidates that satisfy the
+// required classes. It is 1 if no classes are required and for passphrases.
+func (g *Generator) AcceptanceRate() float64 {
+	if g.Passphrase() || len(g.required) == 0 {
+		return 1
+	}
+	total := new(big.Int).Exp(big.NewInt(int64(len(g.charset))), big.NewInt(int64(g.length)), nil)
+	if g.positions != nil {
+		total.SetInt64(1)
+		for _, cs := range g.positions {
+			total.Mul(total, big.NewInt(int64(cs.Len())))
+		}
+	}
+	rate, _ := new(big.Rat).SetFrac(g.Possibilities(), total).Float64()
+	return rate
+}
+
+// Possibilities returns the number of distinct passwords the generator can
+// produce. When transforms add a fractional number of bits or words are
+// weighted, the result is an approximation.
+func (g *Generator) Possibilities() *big.Int {
+	if !g.Passphrase() {
+		return g.charsetPossibilities()
+	}
+
+	n := new(big.Int).Exp(big.NewInt(int64(len(g.wordlist))), big.NewInt(int64(g.words)), nil)
+	if g.weights != nil {
+		// a weighted passphrase is as hard to guess as one of 2^entropy
+		// equally likely ones
+		e := g.Entropy()
+		whole := math.Floor(e)
+		new(big.Float).SetMantExp(big.NewFloat(math.Exp2(e-whole)), int(whole)).Int(n)
+		return n
+	}
+	extra := 0.0
+	for _, t := range g.transforms {
+		extra += t.Entropy(g.wordlist, g.words)
+	}
+	if extra == 0 {
+		return n
+	}
+	f := new(big.Float).SetInt(n)
+	f.Mul(f, new(big.Float).SetFloat64(math.Exp2(extra)))
+	f.Int(n)
+	return n
+}
+
+// charsetPossibilities counts the passwords of the configured length that
+// contain every required class, using the inclusion-exclusion principle over
+// the sets of classes that are missing.
+func (g *Generator) charsetPossibilities() *big.Int {
+	if g.positions != nil {
+		return g.positionPossibilities()
+	}
+	charset := Charset{g.charset}
+	sizes := make([]int, len(g.required))
+	for i, c := range g.required {
+		sizes[i] = charset.Intersect(g.classSet(c)).Len()
+	}
+
+	total := new(big.Int)
+	length := big.NewInt(int64(g.length))
+	for mask := range 1 << len(sizes) {
+		n := len(g.charset)
+		for i, size := range sizes {
+			if mask&(1<<i) != 0 {
+				n -= size
+			}
+		}
+		term := new(big.Int).Exp(big.NewInt(int64(n)), length, nil)
+		if bits.OnesCount(uint(mask))%2 == 0 {
+			total.Add(total, term)
+		} else {
+			total.Sub(total, term)
+		}
+	}
+	return total
+}
+
+// positionPossibilities is like charsetPossibilities for generators configured
+// with [WithPositions]: for each set of missing classes, the number of
+// passwords avoiding them is the product over positions of the characters
+// left at that position.
+func (g *Generator) positionPossibilities() *big.Int {
+	total := new(big.Int)
+	for mask := range 1 << len(g.required) {
+		var missing []Charset
+		for i, c := range g.required {
+			if mask&(1<<i) != 0 {
+				missing = append(missing, g.classSet(c))
+			}
+		}
+		term := big.NewInt(1)
+		for _, cs := range g.positions {
+			term.Mul(term, big.NewInt(int64(cs.Subtract(missing...).Len())))
+		}
+		if len(missing)%2 == 0 {
+			total.Add(total, term)
+		} else {
+			total.Sub(total, term)
+		}
+	}
+	return total
+}
+
+// log2Int returns log2(n) for arbitrarily large n.
+func log2Int(n *big.Int) float64 {
+	if n.Sign() <= 0 {
+		return 0
+	}
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(n).MantExp(mant)
+	m, _ := mant.Float64()
+	return math.Log2(m) + float64(exp)
+}
@@ -3,9 +3,13 @@
 package genpass
 
 import (
+	"bufio"
 	"crypto/rand"
+	"errors"
+	"io"
 	"math/big"
 	"slices"
+	"unicode/utf8"
 )
 
 const (
@@ -29,7 +33,7 @@ func Generate(length int, charset string) string {
 	charsetLen := big.NewInt(int64(len(chars)))
 	password := make([]rune, length)
 	for i := range length {
-		j, err := rand.Int(rand.Reader, charsetLen)
+		j, err := rand.Int(entropySource{}, charsetLen)
 		if err != nil {
 			// should never happen
 			panic(err)
@@ -37,13 +41,96 @@ func Generate(length int, charset string) string {
 		password[i] = chars[j.Int64()]
 	}
 
+	if observing() {
+		e := GenerateEvent{Length: length, Entropy: float64(length) * multisetEntropy(chars)}
+		notify(func(o Observer) { o.OnGenerate(e) })
+	}
 	return string(password)
 }
 
+// GenerateTo writes a random password of the specified length using the given
+// charset to w. Unlike [Generate], the password is never held in memory in its
+// entirety, so GenerateTo is suitable for generating very large outputs such as
+// test fixtures or key files.
+func GenerateTo(w io.Writer, charset string, length int) (err error) {
+	chars := []rune(charset)
+	if len(chars) == 0 {
+		return errors.New("genpass: empty charset")
+	}
+	slices.Sort(chars)
+	defer func() {
+		if err == nil && observing() {
+			e := GenerateEvent{Length: length, Entropy: float64(length) * multisetEntropy(chars)}
+			notify(func(o Observer) { o.OnGenerate(e) })
+		}
+		notify(func(o Observer) { o.OnSinkWrite(SinkWriteEvent{Sink: "writer", Count: 1, Err: err}) })
+	}()
+
+	bw := bufio.NewWriterSize(w, entropyBatchSize)
+	if len(chars) <= 256 && chars[len(chars)-1] < utf8.RuneSelf {
+		return generateASCIITo(bw, chars, length)
+	}
+	entropy := newEntropyReader(entropyBatchSize)
+	for range length {
+		j, err := entropy.Intn(len(chars))
+		if err != nil {
+			return err
+		}
+		if c := chars[j]; c < utf8.RuneSelf {
+			err = bw.WriteByte(byte(c))
+		} else {
+			_, err = bw.WriteRune(c)
+		}
+		if err != nil {
+			return &SinkError{Sink: "writer", Err: err}
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return &SinkError{Sink: "writer", Err: err}
+	}
+	return nil
+}
+
+// generateASCIITo is GenerateTo for charsets of at most 256 ASCII characters,
+// which maps each random byte to a character through a table instead of
+// drawing characters one at a time.
+func generateASCIITo(bw *bufio.Writer, chars []rune, length int) error {
+	// bytes from the final partial block are rejected to avoid modulo bias
+	limit := 256 - 256%len(chars)
+	var table [256]byte
+	for b := range limit {
+		table[b] = byte(chars[b%len(chars)])
+	}
+
+	in := make([]byte, entropyBatchSize)
+	out := make([]byte, 0, entropyBatchSize)
+	for remaining := length; remaining > 0; {
+		random := in[:min(len(in), remaining)]
+		if _, err := io.ReadFull(entropySource{}, random); err != nil {
+			return entropyError(err)
+		}
+		out = out[:0]
+		for _, b := range random {
+			if int(b) < limit {
+				out = append(out, table[b])
+			}
+		}
+		if _, err := bw.Write(out); err != nil {
+			return &SinkError{Sink: "writer", Err: err}
+		}
+		remaining -= len(out)
+	}
+	if err := bw.Flush(); err != nil {
+		return &SinkError{Sink: "writer", Err: err}
+	}
+	return nil
+}
+
 // NormalizeCharset normalizes the charset by removing duplicates and sorting
 // the characters in ascending order.
 func NormalizeCharset(charset string) string {
 	chars := []rune(charset)
 	slices.Sort(chars)
-	return string(chars)
+	return string(slices.Compact(chars))
 }
@@ -1,3 +1,15 @@
 module github.com/calico32/genpass
 
 go 1.24.1
+
+require (
+	filippo.io/age v1.2.1
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	golang.org/x/crypto v0.40.0
+)
+
+require (
+	filippo.io/edwards25519 v1.1.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
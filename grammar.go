@@ -0,0 +1,173 @@
+package genpass
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed wordlists/grammar.txt
+var grammarWords string
+
+// GrammarPart is a slot of a [Grammar], filled with one of its choices chosen
+// uniformly at random.
+type GrammarPart struct {
+	Name    string
+	Choices []string
+}
+
+// Grammar describes the structure of a memorable secret as a sequence of
+// parts, such as an adjective, a noun, a verb, and a number, which produces
+// secrets like "BraveOtterJumps73".
+type Grammar struct {
+	Parts []GrammarPart
+	// Separator is placed between parts.
+	Separator string
+	// Capitalize uppercases the first letter of every part. Without it or a
+	// separator, different choices can produce the same secret, like "sun"
+	// and "set" versus "sunset", which makes the entropy an overestimate.
+	Capitalize bool
+}
+
+var (
+	grammarPartsMu sync.RWMutex
+	grammarParts   = map[string]GrammarPart{}
+)
+
+func init() {
+	words := map[string][]string{}
+	for _, line := range strings.Split(grammarWords, "\n") {
+		tag, word, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok && !strings.HasPrefix(tag, "#") {
+			words[tag] = append(words[tag], word)
+		}
+	}
+	for tag, list := range words {
+		RegisterGrammarPart(tag, list)
+	}
+
+	var numbers, digits, symbols []string
+	for i := range 100 {
+		numbers = append(numbers, fmt.Sprintf("%02d", i))
+	}
+	for _, r := range CharsetNum {
+		digits = append(digits, string(r))
+	}
+	for _, r := range CharsetSpecial {
+		symbols = append(symbols, string(r))
+	}
+	RegisterGrammarPart("number", numbers)
+	RegisterGrammarPart("digit", digits)
+	RegisterGrammarPart("symbol", symbols)
+}
+
+// RegisterGrammarPart makes a part available by name to [LookupGrammarPart]
+// and [ParseGrammar]. Duplicate choices are removed. Registering a part with
+// an existing name replaces it.
+func RegisterGrammarPart(name string, choices []string) {
+	choices = slices.Clone(choices)
+	slices.Sort(choices)
+	choices = slices.Compact(choices)
+	name = strings.ToLower(name)
+
+	grammarPartsMu.Lock()
+	defer grammarPartsMu.Unlock()
+	grammarParts[name] = GrammarPart{Name: name, Choices: choices}
+}
+
+// LookupGrammarPart returns the grammar part registered under name. Names are
+// not case-sensitive. The built-in parts are "adjective", "noun", "verb",
+// "number" (00 to 99), "digit", and "symbol".
+func LookupGrammarPart(name string) (GrammarPart, bool) {
+	grammarPartsMu.RLock()
+	defer grammarPartsMu.RUnlock()
+	p, ok := grammarParts[strings.ToLower(name)]
+	return p, ok
+}
+
+// GrammarParts returns the names of all registered grammar parts in sorted
+// order.
+func GrammarParts() []string {
+	grammarPartsMu.RLock()
+	defer grammarPartsMu.RUnlock()
+	names := make([]string, 0, len(grammarParts))
+	for name := range grammarParts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseGrammar parses the names of registered parts separated by hyphens,
+// such as "adjective-noun-verb-number-symbol", into a capitalized grammar
+// without a separator.
+func ParseGrammar(spec string) (Grammar, error) {
+	g := Grammar{Capitalize: true}
+	for _, name := range strings.Split(spec, "-") {
+		p, ok := LookupGrammarPart(strings.TrimSpace(name))
+		if !ok {
+			return Grammar{}, fmt.Errorf("genpass: unknown grammar part %q (available: %s)", name, strings.Join(GrammarParts(), ", "))
+		}
+		g.Parts = append(g.Parts, p)
+	}
+	return g, nil
+}
+
+// Entropy returns the entropy of secrets generated from g in bits. Choices
+// that appear more than once in a part are counted by how likely they are.
+func (g Grammar) Entropy() float64 {
+	total := 0.0
+	for _, t := range g.EntropyDerivation() {
+		total += t.Bits
+	}
+	return total
+}
+
+// EntropyDerivation breaks [Grammar.Entropy] down into the contribution of
+// each part.
+func (g Grammar) EntropyDerivation() []EntropyTerm {
+	terms := make([]EntropyTerm, len(g.Parts))
+	for i, p := range g.Parts {
+		terms[i] = EntropyTerm{
+			Source:  fmt.Sprintf("part %d (%s)", i+1, p.Name),
+			Formula: choiceFormula(1, p.Choices),
+			Bits:    multisetEntropy(p.Choices),
+		}
+	}
+	return terms
+}
+
+// GenerateFromGrammar generates a secret with the structure of g and returns
+// it with its entropy in bits. Like [Generate], it panics if a part has no
+// choices or the system's random number generator fails.
+func GenerateFromGrammar(g Grammar) (string, float64) {
+	entropy := entropyPool.Get().(*entropyReader)
+	defer entropyPool.Put(entropy)
+
+	parts := make([]string, len(g.Parts))
+	for i, p := range g.Parts {
+		if len(p.Choices) == 0 {
+			panic(fmt.Sprintf("genpass: grammar part %q has no choices", p.Name))
+		}
+		j, err := entropy.Intn(len(p.Choices))
+		if err != nil {
+			panic(err)
+		}
+		parts[i] = p.Choices[j]
+		if g.Capitalize {
+			r, size := utf8.DecodeRuneInString(parts[i])
+			parts[i] = string(unicode.ToUpper(r)) + parts[i][size:]
+		}
+	}
+	s, e := strings.Join(parts, g.Separator), g.Entropy()
+
+	if observing() {
+		ev := GenerateEvent{Passphrase: true, Length: utf8.RuneCountInString(s), Entropy: e}
+		notify(func(o Observer) { o.OnGenerate(ev) })
+	}
+	return s, e
+}
@@ -0,0 +1,22 @@
+package genpass
+
+import "strings"
+
+// Group splits s into groups of size characters joined by sep, e.g.
+// Group("abcdefgh", 4, "-") returns "abcd-efgh". If size is not positive, s is
+// returned unchanged.
+func Group(s string, size int, sep string) string {
+	chars := []rune(s)
+	if size <= 0 || len(chars) <= size {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(chars); i += size {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(string(chars[i:min(i+size, len(chars))]))
+	}
+	return sb.String()
+}
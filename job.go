@@ -0,0 +1,197 @@
+package genpass
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// DefaultFalsePositiveRate is the default false positive rate of the filter
+// used by jobs to reject duplicates. A false positive discards a secret that
+// wasn't a duplicate, so it only costs time.
+const DefaultFalsePositiveRate = 1e-6
+
+// JobSpec describes a batch job: a generator configuration and how many
+// unique secrets to write where.
+type JobSpec struct {
+	Config
+
+	// Count is the number of secrets to generate.
+	Count int `json:"count"`
+	// Output is the path of the file the secrets are written to.
+	Output string `json:"output"`
+	// State is the path of the file the job's progress is saved to. The
+	// default is Output with ".state" appended.
+	State string `json:"state,omitempty"`
+	// SyncEvery is the number of secrets written between saves of the job's
+	// state. Since the state includes the duplicate filter, saving it is
+	// expensive; the default is 1% of Count, but at least
+	// [DefaultSyncEvery].
+	SyncEvery int `json:"syncEvery,omitempty"`
+	// FalsePositiveRate is the false positive rate of the duplicate filter.
+	// The default is [DefaultFalsePositiveRate].
+	FalsePositiveRate float64 `json:"falsePositiveRate,omitempty"`
+}
+
+// StatePath returns the path of the job's state file.
+func (s JobSpec) StatePath() string {
+	if s.State != "" {
+		return s.State
+	}
+	return s.Output + ".state"
+}
+
+// fingerprint identifies the parts of the spec that affect the output, so
+// that a job isn't resumed with a different spec.
+func (s JobSpec) fingerprint() (string, error) {
+	data, err := json.Marshal(struct {
+		Config
+		Count             int
+		FalsePositiveRate float64
+	}{s.Config, s.Count, s.FalsePositiveRate})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// JobState is the persisted progress of a job.
+type JobState struct {
+	// Spec is the fingerprint of the spec the job was started with.
+	Spec string
+	// Progress is the number of secrets and bytes durably written.
+	Progress Checkpoint
+	// Filter contains every secret written so far.
+	Filter *BloomFilter
+}
+
+// ErrJobExists is returned by [StartJob] when the job's state file already
+// exists.
+var ErrJobExists = errors.New("genpass: job already started")
+
+// ErrNoJob is returned by [ResumeJob] when the job's state file doesn't exist.
+var ErrNoJob = errors.New("genpass: job not started")
+
+// LoadJobState reads a job state file.
+func LoadJobState(path string) (*JobState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var state JobState
+	if err := gob.NewDecoder(f).Decode(&state); err != nil {
+		return nil, fmt.Errorf("genpass: invalid job state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+func (s *JobState) save(path string) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(s); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// StartJob runs a new job, writing spec.Count unique secrets to spec.Output.
+// Progress is saved periodically so that an interrupted job can be continued
+// with [ResumeJob]. When the job completes, the state file is kept so that
+// resuming a finished job is a no-op.
+func StartJob(spec JobSpec) (*JobState, error) {
+	if _, err := os.Stat(spec.StatePath()); err == nil {
+		return nil, ErrJobExists
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	fp, err := spec.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	p := spec.FalsePositiveRate
+	if p <= 0 {
+		p = DefaultFalsePositiveRate
+	}
+	state := &JobState{Spec: fp, Filter: NewBloomFilter(spec.Count, p)}
+	return state, runJob(spec, state)
+}
+
+// ResumeJob continues a job started with [StartJob] from its last saved
+// state. Output written after the state was saved is discarded and generated
+// again.
+func ResumeJob(spec JobSpec) (*JobState, error) {
+	state, err := LoadJobState(spec.StatePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoJob
+	}
+	if err != nil {
+		return nil, err
+	}
+	fp, err := spec.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	if fp != state.Spec {
+		return state, errors.New("genpass: job spec has changed since the job was started")
+	}
+	return state, runJob(spec, state)
+}
+
+func runJob(spec JobSpec, state *JobState) error {
+	if spec.Output == "" {
+		return errors.New("genpass: job has no output")
+	}
+	gen, err := spec.Generator()
+	if err != nil {
+		return err
+	}
+	syncEvery := spec.SyncEvery
+	if syncEvery <= 0 {
+		syncEvery = max(DefaultSyncEvery, spec.Count/100)
+	}
+
+	f, err := os.OpenFile(spec.Output, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// discard anything written after the state was saved
+	if err := f.Truncate(state.Progress.Offset); err != nil {
+		return err
+	}
+	if _, err := f.Seek(state.Progress.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	path := spec.StatePath()
+	_, err = WriteBatch(f, gen, BatchConfig{
+		Count:     spec.Count,
+		SyncEvery: syncEvery,
+		Resume:    state.Progress,
+		Unique:    state.Filter,
+		OnSync: func(cp Checkpoint) error {
+			state.Progress = cp
+			return state.save(path)
+		},
+	})
+	return err
+}
@@ -0,0 +1,209 @@
+package genpass
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"filippo.io/age/armor"
+	"golang.org/x/crypto/ssh"
+)
+
+// KeyKind is a kind of keypair generated by [GenerateKeypair].
+type KeyKind string
+
+const (
+	KeyEd25519 KeyKind = "ed25519"
+	KeyRSA     KeyKind = "rsa"
+	KeyAge     KeyKind = "age"
+)
+
+// Keypair is a generated keypair in its usual text formats.
+type Keypair struct {
+	Kind KeyKind
+	// PrivateKey is an OpenSSH private key for SSH keys or an age identity file
+	// for age keys. If a passphrase was given, it is encrypted.
+	PrivateKey []byte
+	// PublicKey is a line in authorized_keys format for SSH keys or a
+	// recipient for age keys.
+	PublicKey string
+	// Fingerprint is the SHA256 fingerprint of SSH keys. It is empty for age
+	// keys, whose recipient serves the same purpose.
+	Fingerprint string
+}
+
+type keypairOptions struct {
+	passphrase string
+	comment    string
+	rsaBits    int
+}
+
+// KeypairOption configures [GenerateKeypair].
+type KeypairOption func(*keypairOptions)
+
+// WithKeyPassphrase encrypts the private key with passphrase. A strong
+// passphrase can be created with [NewGenerator] and [WithWords].
+func WithKeyPassphrase(passphrase string) KeypairOption {
+	return func(o *keypairOptions) {
+		o.passphrase = passphrase
+	}
+}
+
+// WithKeyComment sets the comment of an SSH key, conventionally user@host.
+func WithKeyComment(comment string) KeypairOption {
+	return func(o *keypairOptions) {
+		o.comment = comment
+	}
+}
+
+// WithRSABits sets the size of RSA keys. The default is 4096 bits.
+func WithRSABits(bits int) KeypairOption {
+	return func(o *keypairOptions) {
+		o.rsaBits = bits
+	}
+}
+
+// GenerateKeypair generates a new keypair of the given kind.
+//
+// Keys are always generated from the operating system's random number
+// generator, regardless of the source selected with [UseEntropySource]: the
+// standard library ignores other sources for RSA keys, as age does for its
+// keys.
+func GenerateKeypair(kind KeyKind, opts ...KeypairOption) (*Keypair, error) {
+	o := keypairOptions{rsaBits: 4096}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch kind {
+	case KeyEd25519:
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return sshKeypair(kind, priv, o)
+	case KeyRSA:
+		if o.rsaBits < 2048 {
+			return nil, fmt.Errorf("genpass: RSA keys must be at least 2048 bits, got %d", o.rsaBits)
+		}
+		priv, err := rsa.GenerateKey(rand.Reader, o.rsaBits)
+		if err != nil {
+			return nil, err
+		}
+		return sshKeypair(kind, priv, o)
+	case KeyAge:
+		return ageKeypair(o)
+	}
+	return nil, fmt.Errorf("genpass: unknown key kind %q", kind)
+}
+
+func sshKeypair(kind KeyKind, priv any, o keypairOptions) (*Keypair, error) {
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	var block *pem.Block
+	if o.passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, o.comment, []byte(o.passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(priv, o.comment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pub := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(signer.PublicKey())), "\n")
+	if o.comment != "" {
+		pub += " " + o.comment
+	}
+	return &Keypair{
+		Kind:        kind,
+		PrivateKey:  pem.EncodeToMemory(block),
+		PublicKey:   pub,
+		Fingerprint: ssh.FingerprintSHA256(signer.PublicKey()),
+	}, nil
+}
+
+func ageKeypair(o keypairOptions) (*Keypair, error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+	recipient := identity.Recipient().String()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# created: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&buf, "# public key: %s\n", recipient)
+	fmt.Fprintf(&buf, "%s\n", identity)
+	private := buf.Bytes()
+
+	if o.passphrase != "" {
+		private, err = ageEncryptPassphrase(private, o.passphrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Keypair{Kind: KeyAge, PrivateKey: private, PublicKey: recipient}, nil
+}
+
+// ageEncryptPassphrase encrypts data to an armored age file protected by
+// passphrase, like "age -p -a".
+func ageEncryptPassphrase(data []byte, passphrase string) ([]byte, error) {
+	r, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return ageEncrypt(data, r)
+}
+
+// EncryptFor encrypts secret to an armored age file that any of recipients
+// can decrypt, like "age -a -r". Recipients are age public keys ("age1...")
+// or SSH public keys in authorized_keys format.
+func EncryptFor(recipients []string, secret []byte) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("genpass: no recipients")
+	}
+	rs := make([]age.Recipient, len(recipients))
+	for i, s := range recipients {
+		s = strings.TrimSpace(s)
+		var err error
+		if strings.HasPrefix(s, "age1") {
+			rs[i], err = age.ParseX25519Recipient(s)
+		} else {
+			rs[i], err = agessh.ParseRecipient(s)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("genpass: invalid recipient %q: %w", s, err)
+		}
+	}
+	return ageEncrypt(secret, rs...)
+}
+
+// ageEncrypt encrypts data to an armored age file for recipients.
+func ageEncrypt(data []byte, recipients ...age.Recipient) ([]byte, error) {
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := aw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
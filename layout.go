@@ -0,0 +1,113 @@
+package genpass
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Layout describes the characters that are easy to type on a keyboard: those
+// reachable directly or with Shift, without AltGr, dead keys, or switching to
+// another page of symbols.
+type Layout struct {
+	// Name identifies the layout, e.g. "de".
+	Name string
+	// Description is a human-readable name of the layout.
+	Description string
+	// Chars are the characters that are easy to type.
+	Chars Charset
+}
+
+// LayoutMobile is the name of the layout of phone keyboards. It only has the
+// symbols on the first symbol page of both the iOS and Android keyboards,
+// without quotes, which smart punctuation turns into curly quotes.
+const LayoutMobile = "mobile"
+
+const layoutAlnum = CharsetAlpha + CharsetNum
+
+var (
+	layoutsMu sync.RWMutex
+	layouts   = map[string]Layout{
+		"us": {
+			Name:        "us",
+			Description: "US QWERTY",
+			Chars:       NewCharset(layoutAlnum + "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"),
+		},
+		"de": {
+			Name:        "de",
+			Description: "German QWERTZ",
+			// @ { [ ] } \ ~ | need AltGr, and ^ ` are dead keys
+			Chars: NewCharset(layoutAlnum + "!\"#$%&'()*+,-./:;<=>?_"),
+		},
+		"fr": {
+			Name:        "fr",
+			Description: "French AZERTY",
+			// ~ # { [ | ` \ @ ] } need AltGr, and ^ is a dead key
+			Chars: NewCharset(layoutAlnum + "!\"$%&'()*+,-./:;<=>?_"),
+		},
+		LayoutMobile: {
+			Name:        LayoutMobile,
+			Description: "phone keyboard",
+			Chars:       NewCharset(layoutAlnum + "!$&()-/:;?@,."),
+		},
+	}
+)
+
+// RegisterLayout makes a keyboard layout available by name to
+// [LookupLayout] and [LayoutCharset]. Registering a layout with an existing
+// name replaces it.
+func RegisterLayout(l Layout) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	layouts[strings.ToLower(l.Name)] = l
+}
+
+// LookupLayout returns the keyboard layout registered under name. The
+// built-in layouts are us, de, fr, and mobile. Names are not case-sensitive.
+func LookupLayout(name string) (Layout, bool) {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+	l, ok := layouts[strings.ToLower(name)]
+	return l, ok
+}
+
+// Layouts returns the names of all registered keyboard layouts in sorted
+// order.
+func Layouts() []string {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// LayoutCharset returns the characters of classes that are easy to type on a
+// keyboard layout, or all characters that are easy to type if no classes are
+// given. layout may be a comma-separated list of layouts, such as "us,de", to
+// get the characters that are easy to type on all of them.
+func LayoutCharset(layout string, classes ...Class) (Charset, error) {
+	var chars Charset
+	for i, name := range strings.Split(layout, ",") {
+		l, ok := LookupLayout(strings.TrimSpace(name))
+		if !ok {
+			return Charset{}, fmt.Errorf("genpass: unknown keyboard layout %q", name)
+		}
+		if i == 0 {
+			chars = l.Chars
+		} else {
+			chars = chars.Intersect(l.Chars)
+		}
+	}
+	if len(classes) == 0 {
+		return chars, nil
+	}
+	var set Charset
+	for _, c := range classes {
+		set = set.Union(c.Set())
+	}
+	return set.Intersect(chars), nil
+}
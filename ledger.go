@@ -0,0 +1,201 @@
+package genpass
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// LedgerEntry is the metadata recorded about a generated secret. The secret
+// itself is never stored; only a salted fingerprint that can be used to check
+// whether a given secret matches the entry.
+type LedgerEntry struct {
+	Label       string    `json:"label"`
+	Created     time.Time `json:"created"`
+	Length      int       `json:"length"`
+	Entropy     float64   `json:"entropy"`
+	Salt        string    `json:"salt"`
+	Fingerprint string    `json:"fingerprint"`
+	// Tags are the tags of the secret in the [Manifest] it was created from.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Matches reports whether secret is the secret recorded by the entry.
+func (e LedgerEntry) Matches(secret string) bool {
+	salt, err := hex.DecodeString(e.Salt)
+	if err != nil {
+		return false
+	}
+	want, err := hex.DecodeString(e.Fingerprint)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(fingerprint(salt, secret), want)
+}
+
+func fingerprint(salt []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(secret))
+	return mac.Sum(nil)
+}
+
+// Ledger is a local record of when secrets were generated, used to find secrets
+// that are due for rotation. Each label may have several entries; the most
+// recent one is considered current.
+type Ledger struct {
+	path    string
+	Entries []LedgerEntry `json:"entries"`
+}
+
+// DefaultLedgerPath returns the default location of the ledger file in the
+// user's configuration directory.
+func DefaultLedgerPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "genpass", "ledger.json"), nil
+}
+
+// OpenLedger reads the ledger stored at path. If the file doesn't exist, an
+// empty ledger is returned and the file is created on the first [Ledger.Save].
+func OpenLedger(path string) (*Ledger, error) {
+	l := &Ledger{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Remember records a new entry for secret under label and returns it. The
+// ledger must be saved with [Ledger.Save] for the entry to persist.
+func (l *Ledger) Remember(label, secret string, entropy float64) (LedgerEntry, error) {
+	if label == "" {
+		return LedgerEntry{}, errors.New("genpass: ledger entries require a label")
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return LedgerEntry{}, err
+	}
+	e := LedgerEntry{
+		Label:       label,
+		Created:     time.Now().UTC().Truncate(time.Second),
+		Length:      utf8.RuneCountInString(secret),
+		Entropy:     entropy,
+		Salt:        hex.EncodeToString(salt),
+		Fingerprint: hex.EncodeToString(fingerprint(salt, secret)),
+	}
+	l.Entries = append(l.Entries, e)
+	return e, nil
+}
+
+// Current returns the most recent entry for each label, sorted by label.
+// Creation times are only kept to the second, so of entries created in the
+// same second, the one added last is the most recent.
+func (l *Ledger) Current() []LedgerEntry {
+	latest := map[string]LedgerEntry{}
+	for _, e := range l.Entries {
+		if cur, ok := latest[e.Label]; !ok || !e.Created.Before(cur.Created) {
+			latest[e.Label] = e
+		}
+	}
+	entries := make([]LedgerEntry, 0, len(latest))
+	for _, e := range latest {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b LedgerEntry) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+	return entries
+}
+
+// Due returns the current entries that were created more than age before now,
+// oldest first.
+func (l *Ledger) Due(age time.Duration, now time.Time) []LedgerEntry {
+	var due []LedgerEntry
+	for _, e := range l.Current() {
+		if now.Sub(e.Created) > age {
+			due = append(due, e)
+		}
+	}
+	slices.SortFunc(due, func(a, b LedgerEntry) int {
+		return a.Created.Compare(b.Created)
+	})
+	return due
+}
+
+// Save writes the ledger back to the file it was opened from. The file is
+// readable only by the current user.
+func (l *Ledger) Save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".ledger-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
+
+// ParseAge parses a duration like [time.ParseDuration], additionally accepting
+// a single number with a d (day), w (week), or y (365-day year) suffix. The
+// duration must be positive.
+func ParseAge(s string) (time.Duration, error) {
+	d, err := parseAge(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("genpass: age must be positive")
+	}
+	return d, nil
+}
+
+func parseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+		"y": 365 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(v * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(s)
+}
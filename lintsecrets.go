@@ -0,0 +1,195 @@
+package genpass
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultLintMinBits is the default mask-attack work, in bits, below which
+// [LintSecrets] reports a secret as weak. It is the lower bound of
+// [StrengthFair] on the default scale.
+const DefaultLintMinBits = minEntropyFair
+
+// LintReason is the reason a secret is reported by [LintSecrets].
+type LintReason string
+
+const (
+	// LintCommon means the secret is a common password, possibly with
+	// leet-speak substitutions.
+	LintCommon LintReason = "common-password"
+	// LintLowEntropy means a mask attack exhausts the secret with less work
+	// than the minimum.
+	LintLowEntropy LintReason = "low-entropy"
+)
+
+// LintFinding is a weak secret found in a configuration file. It never holds
+// the secret itself.
+type LintFinding struct {
+	Path string
+	// Line is the 1-based line number of the secret.
+	Line int
+	// Key is the name of the setting the secret is assigned to.
+	Key    string
+	Reason LintReason
+	// Analysis is the result of [Analyze] for the secret.
+	Analysis Analysis
+}
+
+// lintAssignment matches assignments to settings whose names suggest a
+// secret, in env, INI, TOML, YAML, JSON, and similar files.
+var lintAssignment = regexp.MustCompile(`(?i)^\s*(?:export\s+|[{\[,]\s*)?["']?([\w.\-]*(?:pass(?:word|wd|phrase)?|pwd|secret|token|api[_\-]?key|credentials?)[\w.\-]*)["']?\s*[:=]\s*(.*)$`)
+
+// lintSkipSuffixes are the last components of the names of settings that
+// describe a secret rather than hold one, like "password_file" or
+// "tokenTTL".
+var lintSkipSuffixes = []string{
+	"file", "path", "env", "name", "id", "url", "uri", "type", "policy",
+	"length", "min", "max", "bits", "count", "age", "days", "ttl", "timeout",
+	"expiry", "expires", "enabled", "required", "header", "field", "prompt",
+	"pattern", "regex", "rotation",
+}
+
+// lintSkipKey reports whether key ends in one of [lintSkipSuffixes] as a
+// separate component: after a separator, or capitalized in camel case.
+func lintSkipKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, suffix := range lintSkipSuffixes {
+		i := len(key) - len(suffix)
+		if i <= 0 || !strings.HasSuffix(lower, suffix) {
+			continue
+		}
+		camel := key[i-1] >= 'a' && key[i-1] <= 'z' && key[i] >= 'A' && key[i] <= 'Z'
+		if camel || strings.ContainsRune("_.-", rune(key[i-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// commonPasswordSet holds [CommonPasswords] with leet-speak undone.
+var commonPasswordSet = sync.OnceValue(func() map[string]bool {
+	common := make(map[string]bool, len(CommonPasswords))
+	for _, p := range CommonPasswords {
+		common[normalizeLeet(p)] = true
+	}
+	return common
+})
+
+// LintSecrets scans a configuration file read from r for secrets that are
+// assigned literally to settings like "password" or "API_TOKEN" and are
+// common passwords or weaker than minBits according to [Analyze]. Values that
+// reference a secret kept elsewhere, like "${DB_PASSWORD}" or "{{ .token }}",
+// are skipped. path is only used to fill in the findings.
+func LintSecrets(r io.Reader, path string, minBits float64) ([]LintFinding, error) {
+	var findings []LintFinding
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		m := lintAssignment.FindStringSubmatch(scanner.Text())
+		if m == nil || lintSkipKey(m[1]) {
+			continue
+		}
+		value, ok := lintValue(m[2])
+		if !ok {
+			continue
+		}
+		f := LintFinding{Path: path, Line: line, Key: m[1], Analysis: Analyze(value)}
+		switch {
+		case commonPasswordSet()[normalizeLeet(value)]:
+			f.Reason = LintCommon
+		case f.Analysis.Bits < minBits:
+			f.Reason = LintLowEntropy
+		default:
+			continue
+		}
+		findings = append(findings, f)
+	}
+	return findings, scanner.Err()
+}
+
+// lintValue extracts the literal from the right-hand side of an assignment,
+// removing quotes, trailing commas, and comments. It reports false for empty
+// values and references to secrets kept elsewhere.
+func lintValue(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return "", false
+		}
+		s = s[1 : end+1]
+	} else {
+		if i := strings.Index(s, " #"); i >= 0 {
+			s = s[:i]
+		}
+		s = strings.TrimRight(strings.TrimSpace(s), ",;")
+	}
+	switch {
+	case s == "", s == "null", s == "~", s == "{", s == "[",
+		strings.EqualFold(s, "true"), strings.EqualFold(s, "false"),
+		strings.HasPrefix(s, "$"), strings.HasPrefix(s, "{{"), strings.HasPrefix(s, "%("),
+		strings.HasPrefix(s, "!"), strings.HasPrefix(s, "ENC["),
+		strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">"):
+		return "", false
+	}
+	return s, true
+}
+
+// lintExtensions are the extensions of the files [LintSecretsDir] scans, in
+// addition to files named .env or starting with ".env.".
+var lintExtensions = []string{
+	".env", ".ini", ".cfg", ".conf", ".config", ".properties", ".toml",
+	".yaml", ".yml", ".json", ".tfvars", ".tf", ".hcl", ".xml",
+}
+
+// lintSkipDirs are directories [LintSecretsDir] doesn't descend into.
+var lintSkipDirs = []string{".git", ".hg", ".svn", "node_modules", "vendor"}
+
+// lintMaxFileSize is the size above which files are assumed not to be
+// configuration and skipped.
+const lintMaxFileSize = 1 << 20
+
+// LintSecretsDir runs [LintSecrets] on the configuration files under root,
+// which may also be a single file. Configuration files are recognized by
+// their extension; version control and dependency directories are skipped,
+// as are files that are larger than 1 MiB or not UTF-8.
+func LintSecretsDir(root string, minBits float64) ([]LintFinding, error) {
+	var findings []LintFinding
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && slices.Contains(lintSkipDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if path != root && !slices.Contains(lintExtensions, strings.ToLower(filepath.Ext(name))) && name != ".env" && !strings.HasPrefix(name, ".env.") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > lintMaxFileSize {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(data) {
+			return nil
+		}
+		f, err := LintSecrets(strings.NewReader(string(data)), path, minBits)
+		findings = append(findings, f...)
+		return err
+	})
+	return findings, err
+}
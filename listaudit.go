@@ -0,0 +1,105 @@
+package genpass
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ListAudit is the result of [AuditList]. Secrets are identified by their
+// zero-based index in the audited list, so that the report can be shared
+// without exposing them.
+type ListAudit struct {
+	// Total is the number of secrets audited.
+	Total int
+	// Duplicates lists groups of identical secrets.
+	Duplicates [][]int
+	// NearDuplicates lists groups of different secrets that only differ in
+	// case, leet-speak substitutions, or trailing digits and symbols, like
+	// "Summer2023!" and "summer2024". Such secrets are likely guessable from
+	// each other.
+	NearDuplicates [][]int
+	// Common lists secrets that are common passwords, ignoring case and
+	// leet-speak substitutions.
+	Common []int
+	// Histogram counts the secrets in bins of EntropyBinWidth bits of
+	// entropy, as estimated by [Analyze]; the last bin holds everything
+	// above. The estimate assumes a brute-force attack, so it overstates the
+	// strength of human-chosen secrets.
+	Histogram [EntropyBins]int
+}
+
+// Bins of the entropy histogram of a [ListAudit].
+const (
+	EntropyBins     = 9
+	EntropyBinWidth = 16
+)
+
+// AuditList checks a list of existing secrets, such as credentials to be
+// migrated, for reuse and weak choices.
+func AuditList(secrets []string) ListAudit {
+	a := ListAudit{Total: len(secrets)}
+
+	common := commonPasswordSet()
+	exact := map[string][]int{}
+	near := map[string][]int{}
+	for i, s := range secrets {
+		exact[s] = append(exact[s], i)
+		near[nearDuplicateKey(s)] = append(near[nearDuplicateKey(s)], i)
+		if common[normalizeLeet(s)] {
+			a.Common = append(a.Common, i)
+		}
+		bin := int(Analyze(s).Bits) / EntropyBinWidth
+		a.Histogram[min(bin, EntropyBins-1)]++
+	}
+
+	for _, group := range exact {
+		if len(group) > 1 {
+			a.Duplicates = append(a.Duplicates, group)
+		}
+	}
+	for _, group := range near {
+		// skip groups that are only exact duplicates
+		if len(group) > 1 && slices.ContainsFunc(group, func(i int) bool { return secrets[i] != secrets[group[0]] }) {
+			a.NearDuplicates = append(a.NearDuplicates, group)
+		}
+	}
+	// report groups in the order of the list
+	byFirst := func(a, b []int) int { return a[0] - b[0] }
+	slices.SortFunc(a.Duplicates, byFirst)
+	slices.SortFunc(a.NearDuplicates, byFirst)
+	return a
+}
+
+// nearDuplicateKey returns s without case, leet-speak substitutions, and
+// trailing digits and symbols, which people commonly vary when forced to
+// change a password.
+func nearDuplicateKey(s string) string {
+	trimmed := strings.TrimRightFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if trimmed == "" {
+		return normalizeLeet(s)
+	}
+	return normalizeLeet(trimmed)
+}
+
+// Flagged returns the number of secrets that are duplicates, near-duplicates,
+// or common passwords.
+func (a ListAudit) Flagged() int {
+	flagged := map[int]bool{}
+	for _, group := range slices.Concat(a.Duplicates, a.NearDuplicates, [][]int{a.Common}) {
+		for _, i := range group {
+			flagged[i] = true
+		}
+	}
+	return len(flagged)
+}
+
+// BinRange returns the range of entropy of a histogram bin, with an infinite
+// upper bound for the last one.
+func (ListAudit) BinRange(bin int) (lo, hi float64) {
+	if bin == EntropyBins-1 {
+		return float64(bin * EntropyBinWidth), math.Inf(1)
+	}
+	return float64(bin * EntropyBinWidth), float64((bin + 1) * EntropyBinWidth)
+}
@@ -0,0 +1,113 @@
+package genpass
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxCharsetLen is the maximum number of distinct characters or words that can
+// be used for generation.
+const MaxCharsetLen int64 = 1 << 32
+
+// LoadCharset reads a charset from r. Whitespace and control characters are
+// ignored, and the result is normalized with [NormalizeCharset]. It returns an
+// error if the input is not valid UTF-8 or contains fewer than two distinct
+// characters.
+func LoadCharset(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(data) {
+		return "", errors.New("genpass: charset is not valid UTF-8")
+	}
+
+	charset := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, string(data))
+	charset = NormalizeCharset(charset)
+
+	if n := utf8.RuneCountInString(charset); n < 2 {
+		return "", fmt.Errorf("genpass: charset must contain at least 2 distinct characters, got %d", n)
+	}
+	return charset, nil
+}
+
+// LoadWordlist reads a wordlist from r, one word per line. Blank lines and
+// lines starting with # are ignored. Lines in diceware format ("11111 word")
+// are accepted and the dice roll is discarded. Words are normalized to NFC and
+// deduplicated ignoring case, keeping the first spelling, so that entries like
+// "Café" and "cafe\u0301" don't count as two words; the result is sorted in
+// the collation order of the current [Locale]. It returns an error if the
+// wordlist contains fewer than two distinct words.
+func LoadWordlist(r io.Reader) ([]string, error) {
+	var words []string
+	seen := map[string]bool{}
+	fold := cases.Fold()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !utf8.ValidString(line) {
+			return nil, errors.New("genpass: wordlist is not valid UTF-8")
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.Trim(fields[0], "0123456789") == "" {
+			fields = fields[1:]
+		}
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("genpass: invalid wordlist line %q", line)
+		}
+		word := norm.NFC.String(fields[0])
+		if key := wordKey(fold, word); !seen[key] {
+			seen[key] = true
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sortWords(words)
+	if len(words) < 2 {
+		return nil, fmt.Errorf("genpass: wordlist must contain at least 2 distinct words, got %d", len(words))
+	}
+	if int64(len(words)) > MaxCharsetLen {
+		return nil, fmt.Errorf("genpass: wordlist must contain at most %d words", MaxCharsetLen)
+	}
+	return words, nil
+}
+
+// wordKey returns the key words are deduplicated by: the case folding of the
+// word, in NFC.
+func wordKey(fold cases.Caser, word string) string {
+	return norm.NFC.String(fold.String(word))
+}
+
+// sortWords sorts words in the collation order of the current [Locale],
+// breaking ties between words that collate equally by their bytes.
+func sortWords(words []string) {
+	c := collate.New(language.Make(CurrentLocale().Tag))
+	slices.SortFunc(words, func(a, b string) int {
+		if n := c.CompareString(a, b); n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+}
@@ -0,0 +1,301 @@
+package genpass
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+)
+
+// Message identifies a human-readable string produced by the package. The
+// text for each message is looked up in the current [Locale] at the time it is
+// formatted.
+type Message string
+
+const (
+	MsgEternity       Message = "eternity"
+	MsgLessThanSecond Message = "less-than-second"
+	MsgUnitSeparator  Message = "unit-separator"
+	MsgDecimalPoint   Message = "decimal-point"
+
+	MsgUnitSecond                    Message = "unit.second"
+	MsgUnitMinute                    Message = "unit.minute"
+	MsgUnitHour                      Message = "unit.hour"
+	MsgUnitDay                       Message = "unit.day"
+	MsgUnitMonth                     Message = "unit.month"
+	MsgUnitYear                      Message = "unit.year"
+	MsgUnitThousandYears             Message = "unit.thousand-years"
+	MsgUnitMillionYears              Message = "unit.million-years"
+	MsgUnitBillionYears              Message = "unit.billion-years"
+	MsgUnitTrillionYears             Message = "unit.trillion-years"
+	MsgUnitQuadrillionYears          Message = "unit.quadrillion-years"
+	MsgUnitQuintillionYears          Message = "unit.quintillion-years"
+	MsgUnitSextillionYears           Message = "unit.sextillion-years"
+	MsgUnitSeptillionYears           Message = "unit.septillion-years"
+	MsgUnitOctillionYears            Message = "unit.octillion-years"
+	MsgUnitNonillionYears            Message = "unit.nonillion-years"
+	MsgUnitDecillionYears            Message = "unit.decillion-years"
+	MsgUnitUndecillionYears          Message = "unit.undecillion-years"
+	MsgUnitDuodecillionYears         Message = "unit.duodecillion-years"
+	MsgUnitTredecillionYears         Message = "unit.tredecillion-years"
+	MsgUnitQuattuordecillionYears    Message = "unit.quattuordecillion-years"
+	MsgUnitQuindecillionYears        Message = "unit.quindecillion-years"
+	MsgUnitSexdecillionYears         Message = "unit.sexdecillion-years"
+	MsgUnitSeptendecillionYears      Message = "unit.septendecillion-years"
+	MsgUnitOctodecillionYears        Message = "unit.octodecillion-years"
+	MsgUnitNovemdecillionYears       Message = "unit.novemdecillion-years"
+	MsgUnitVigintillionYears         Message = "unit.vigintillion-years"
+	MsgUnitUnvigintillionYears       Message = "unit.unvigintillion-years"
+	MsgUnitDuovigintillionYears      Message = "unit.duovigintillion-years"
+	MsgUnitTrevigintillionYears      Message = "unit.trevigintillion-years"
+	MsgUnitQuattuorvigintillionYears Message = "unit.quattuorvigintillion-years"
+	MsgUnitQuinvigintillionYears     Message = "unit.quinvigintillion-years"
+	MsgUnitSexvigintillionYears      Message = "unit.sexvigintillion-years"
+	MsgUnitSeptenvigintillionYears   Message = "unit.septenvigintillion-years"
+	MsgUnitOctovigintillionYears     Message = "unit.octovigintillion-years"
+	MsgUnitNovemvigintillionYears    Message = "unit.novemvigintillion-years"
+	MsgUnitTrigintillionYears        Message = "unit.trigintillion-years"
+	MsgUnitUntrigintillionYears      Message = "unit.untrigintillion-years"
+	MsgUnitDuotrigintillionYears     Message = "unit.duotrigintillion-years"
+	MsgUnitGoogolYears               Message = "unit.googol-years"
+
+	MsgStrengthVeryWeak   Message = "strength.very-weak"
+	MsgStrengthWeak       Message = "strength.weak"
+	MsgStrengthFair       Message = "strength.fair"
+	MsgStrengthStrong     Message = "strength.strong"
+	MsgStrengthVeryStrong Message = "strength.very-strong"
+
+	MsgWarnDuplicateChars Message = "warning.duplicate-chars"
+	MsgWarnLowEntropy     Message = "warning.low-entropy"
+	MsgWarnLowAcceptance  Message = "warning.low-acceptance"
+
+	MsgViolationTooShort      Message = "violation.too-short"
+	MsgViolationTooLong       Message = "violation.too-long"
+	MsgViolationMissingClass  Message = "violation.missing-class"
+	MsgViolationMissingLetter Message = "violation.missing-letter"
+	MsgViolationTooFewClasses Message = "violation.too-few-classes"
+	MsgViolationBlocklisted   Message = "violation.blocklisted"
+	MsgViolationRepetitive    Message = "violation.repetitive"
+)
+
+// PluralForm is a CLDR plural category.
+type PluralForm int
+
+const (
+	PluralOther PluralForm = iota
+	PluralZero
+	PluralOne
+	PluralTwo
+	PluralFew
+	PluralMany
+)
+
+// Locale is a message catalog for a single language.
+//
+// Each message maps plural forms to a format string. Messages that take a
+// count use a single %s verb where the number should appear. Messages without
+// a count only need a [PluralOther] entry; some of them are format strings
+// whose verbs must be kept in the same order.
+type Locale struct {
+	// Tag is the BCP 47 language tag of the locale, e.g. "en" or "de-CH".
+	Tag string
+	// Plural selects the plural form for a count. If nil, [PluralOther] is
+	// always used.
+	Plural func(n *big.Int) PluralForm
+	// Messages contains the translated strings.
+	Messages map[Message]map[PluralForm]string
+}
+
+// T returns the text for msg. If the locale doesn't define msg, the English
+// text is used instead.
+func (l *Locale) T(msg Message) string {
+	return l.lookup(msg, PluralOther)
+}
+
+// N returns the text for msg with the count n substituted in, using the plural
+// form selected for n. If the locale doesn't define msg, the English text is
+// used instead.
+func (l *Locale) N(msg Message, n *big.Int) string {
+	form := PluralOther
+	if l.Plural != nil {
+		form = l.Plural(n)
+	}
+	return fmt.Sprintf(l.lookup(msg, form), n.String())
+}
+
+// decimal returns the text for msg with a decimal count substituted in. The
+// count is made of the integer part n and the fractional digits frac, joined
+// with the locale's decimal point. Fractional counts always use [PluralOther],
+// as in "1.0 hours".
+func (l *Locale) decimal(msg Message, n *big.Int, frac string) string {
+	return fmt.Sprintf(l.lookup(msg, PluralOther), n.String()+l.T(MsgDecimalPoint)+frac)
+}
+
+func (l *Locale) lookup(msg Message, form PluralForm) string {
+	for _, loc := range []*Locale{l, English} {
+		forms, ok := loc.Messages[msg]
+		if !ok {
+			continue
+		}
+		if s, ok := forms[form]; ok {
+			return s
+		}
+		if s, ok := forms[PluralOther]; ok {
+			return s
+		}
+	}
+	return string(msg)
+}
+
+var (
+	localeMu sync.RWMutex
+	locales  = map[string]*Locale{}
+	current  *Locale
+)
+
+// RegisterLocale makes a locale available to [SetLocale]. Registering a
+// locale with the same tag as an existing one replaces it.
+func RegisterLocale(l *Locale) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	locales[l.Tag] = l
+	if current != nil && current.Tag == l.Tag {
+		current = l
+	}
+}
+
+// SetLocale sets the locale used for all human-readable output of the package.
+// The locale must have been registered with [RegisterLocale]; "en" is always
+// available.
+func SetLocale(tag string) error {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	l, ok := locales[tag]
+	if !ok {
+		return fmt.Errorf("genpass: unknown locale %q", tag)
+	}
+	current = l
+	return nil
+}
+
+// LookupLocale returns the locale registered with the given tag.
+func LookupLocale(tag string) (*Locale, bool) {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	l, ok := locales[tag]
+	return l, ok
+}
+
+// Locales returns the tags of all registered locales, sorted.
+func Locales() []string {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// CurrentLocale returns the locale set with [SetLocale].
+func CurrentLocale() *Locale {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	return current
+}
+
+func init() {
+	RegisterLocale(English)
+	current = English
+}
+
+func pluralEnglish(n *big.Int) PluralForm {
+	if n.IsInt64() && n.Int64() == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func other(s string) map[PluralForm]string {
+	return map[PluralForm]string{PluralOther: s}
+}
+
+func oneOther(one, other string) map[PluralForm]string {
+	return map[PluralForm]string{PluralOne: one, PluralOther: other}
+}
+
+// English is the built-in English locale. It is the default locale and the
+// fallback for messages missing from other locales.
+var English = &Locale{
+	Tag:    "en",
+	Plural: pluralEnglish,
+	Messages: map[Message]map[PluralForm]string{
+		MsgEternity:       other("an eternity"),
+		MsgLessThanSecond: other("less than a second"),
+		MsgUnitSeparator:  other(", "),
+		MsgDecimalPoint:   other("."),
+
+		MsgUnitSecond:                    oneOther("%s second", "%s seconds"),
+		MsgUnitMinute:                    oneOther("%s minute", "%s minutes"),
+		MsgUnitHour:                      oneOther("%s hour", "%s hours"),
+		MsgUnitDay:                       oneOther("%s day", "%s days"),
+		MsgUnitMonth:                     oneOther("%s month", "%s months"),
+		MsgUnitYear:                      oneOther("%s year", "%s years"),
+		MsgUnitThousandYears:             other("%s thousand years"),
+		MsgUnitMillionYears:              other("%s million years"),
+		MsgUnitBillionYears:              other("%s billion years"),
+		MsgUnitTrillionYears:             other("%s trillion years"),
+		MsgUnitQuadrillionYears:          other("%s quadrillion years"),
+		MsgUnitQuintillionYears:          other("%s quintillion years"),
+		MsgUnitSextillionYears:           other("%s sextillion years"),
+		MsgUnitSeptillionYears:           other("%s septillion years"),
+		MsgUnitOctillionYears:            other("%s octillion years"),
+		MsgUnitNonillionYears:            other("%s nonillion years"),
+		MsgUnitDecillionYears:            other("%s decillion years"),
+		MsgUnitUndecillionYears:          other("%s undecillion years"),
+		MsgUnitDuodecillionYears:         other("%s duodecillion years"),
+		MsgUnitTredecillionYears:         other("%s tredecillion years"),
+		MsgUnitQuattuordecillionYears:    other("%s quattuordecillion years"),
+		MsgUnitQuindecillionYears:        other("%s quindecillion years"),
+		MsgUnitSexdecillionYears:         other("%s sexdecillion years"),
+		MsgUnitSeptendecillionYears:      other("%s septendecillion years"),
+		MsgUnitOctodecillionYears:        other("%s octodecillion years"),
+		MsgUnitNovemdecillionYears:       other("%s novemdecillion years"),
+		MsgUnitVigintillionYears:         other("%s vigintillion years"),
+		MsgUnitUnvigintillionYears:       other("%s unvigintillion years"),
+		MsgUnitDuovigintillionYears:      other("%s duovigintillion years"),
+		MsgUnitTrevigintillionYears:      other("%s trevigintillion years"),
+		MsgUnitQuattuorvigintillionYears: other("%s quattuorvigintillion years"),
+		MsgUnitQuinvigintillionYears:     other("%s quinvigintillion years"),
+		MsgUnitSexvigintillionYears:      other("%s sexvigintillion years"),
+		MsgUnitSeptenvigintillionYears:   other("%s septenvigintillion years"),
+		MsgUnitOctovigintillionYears:     other("%s octovigintillion years"),
+		MsgUnitNovemvigintillionYears:    other("%s novemvigintillion years"),
+		MsgUnitTrigintillionYears:        other("%s trigintillion years"),
+		MsgUnitUntrigintillionYears:      other("%s untrigintillion years"),
+		MsgUnitDuotrigintillionYears:     other("%s duotrigintillion years"),
+		MsgUnitGoogolYears:               other("%s googol years"),
+
+		MsgStrengthVeryWeak:   other("very weak"),
+		MsgStrengthWeak:       other("weak"),
+		MsgStrengthFair:       other("fair"),
+		MsgStrengthStrong:     other("strong"),
+		MsgStrengthVeryStrong: other("very strong"),
+
+		MsgWarnDuplicateChars: oneOther(
+			"%s duplicate character was removed from the charset",
+			"%s duplicate characters were removed from the charset",
+		),
+		MsgWarnLowEntropy:    other("entropy of %.2f bits is below the recommended %.0f bits"),
+		MsgWarnLowAcceptance: other("only %.2g%% of candidates satisfy the required classes; generation may be slow"),
+
+		MsgViolationTooShort:      oneOther("must be at least %s character long", "must be at least %s characters long"),
+		MsgViolationTooLong:       oneOther("must be at most %s character long", "must be at most %s characters long"),
+		MsgViolationMissingClass:  other("must contain a %s character"),
+		MsgViolationMissingLetter: other("must contain a letter"),
+		MsgViolationTooFewClasses: other("must contain characters from at least %d classes, not %d"),
+		MsgViolationBlocklisted:   other("is a commonly used password"),
+		MsgViolationRepetitive:    other("consists of repeated or sequential characters"),
+	},
+}
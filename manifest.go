@@ -0,0 +1,425 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Manifest declares the secrets that should exist in secret stores, so that
+// they can be created and rotated with [Manifest.Plan] and [Plan.Apply].
+type Manifest struct {
+	Secrets []ManifestSecret `json:"secrets"`
+	// Stores configures how secrets are pushed to each store, by store name.
+	Stores map[string]StorePolicy `json:"stores,omitempty"`
+}
+
+// StorePolicy configures how [Plan.ApplyParallel] pushes secrets to a store.
+// Durations are in the format accepted by [ParseAge] and must be positive.
+type StorePolicy struct {
+	// Timeout limits each attempt to store a secret. The default is no limit.
+	Timeout string `json:"timeout,omitempty"`
+	// Retries is the number of times a failed attempt is retried.
+	Retries int `json:"retries,omitempty"`
+	// Backoff is the delay before the first retry, doubled before each
+	// further retry. The default is [DefaultBackoff].
+	Backoff string `json:"backoff,omitempty"`
+}
+
+// DefaultBackoff is the delay before the first retry of a failed push when a
+// [StorePolicy] doesn't set one.
+const DefaultBackoff = time.Second
+
+// pushPolicy is a parsed [StorePolicy].
+type pushPolicy struct {
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+}
+
+func (p StorePolicy) parse() (pushPolicy, error) {
+	pp := pushPolicy{retries: p.Retries, backoff: DefaultBackoff}
+	if p.Retries < 0 {
+		return pp, errors.New("negative retries")
+	}
+	var err error
+	if p.Timeout != "" {
+		if pp.timeout, err = ParseAge(p.Timeout); err != nil {
+			return pp, fmt.Errorf("invalid timeout %q", p.Timeout)
+		}
+	}
+	if p.Backoff != "" {
+		if pp.backoff, err = ParseAge(p.Backoff); err != nil {
+			return pp, fmt.Errorf("invalid backoff %q", p.Backoff)
+		}
+	}
+	return pp, nil
+}
+
+// ManifestSecret declares a secret in a [Manifest]. It embeds the [Config]
+// the secret is generated with.
+type ManifestSecret struct {
+	// Name is the name the secret is stored under. It is also the label of
+	// the secret in the [Ledger].
+	Name string `json:"name"`
+	// Store is the name of the [SecretStore] the secret is kept in. The
+	// default is "system".
+	Store string `json:"store,omitempty"`
+	// MaxAge is the age after which the secret is rotated, in the format
+	// accepted by [ParseAge]. Secrets without a MaxAge are only created.
+	MaxAge string `json:"maxAge,omitempty"`
+	// Tags are recorded in the ledger with the secret. Changing them rotates
+	// the secret.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Policy is the name of a [Policy] the secret must satisfy.
+	Policy string `json:"policy,omitempty"`
+
+	Config
+}
+
+// StoreName returns the name of the store the secret is kept in.
+func (s ManifestSecret) StoreName() string {
+	if s.Store == "" {
+		return "system"
+	}
+	return s.Store
+}
+
+// PlanAction is what applying a [Plan] does to a secret.
+type PlanAction string
+
+const (
+	// PlanCreate generates a secret that doesn't exist yet.
+	PlanCreate PlanAction = "create"
+	// PlanRotate replaces an existing secret with a new one.
+	PlanRotate PlanAction = "rotate"
+	// PlanSkip leaves an existing secret alone.
+	PlanSkip PlanAction = "skip"
+)
+
+// PlanStep is the action planned for one secret of a [Manifest].
+type PlanStep struct {
+	Secret ManifestSecret
+	Action PlanAction
+	// Reason explains the action, e.g. "older than 90d".
+	Reason string
+	// Age is the age of the existing secret, or 0 if it doesn't exist or
+	// isn't in the ledger.
+	Age time.Duration
+
+	push pushPolicy
+}
+
+// Plan is the set of changes needed to make secret stores match a
+// [Manifest], in the order of the manifest.
+type Plan []PlanStep
+
+// Count returns the number of steps with the given action.
+func (p Plan) Count(action PlanAction) int {
+	n := 0
+	for _, step := range p {
+		if step.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Plan compares the manifest with the current contents of the secret stores
+// and the ledger, which records when each secret was generated. Only the
+// existence of secrets is read from the stores; their age and tags come from
+// the ledger entry whose fingerprint matches the stored secret, so secrets
+// changed outside genpass have no known age.
+func (m Manifest) Plan(ledger *Ledger, now time.Time) (Plan, error) {
+	current := map[string]LedgerEntry{}
+	for _, e := range ledger.Current() {
+		current[e.Label] = e
+	}
+
+	policies := map[string]pushPolicy{}
+	for name, sp := range m.Stores {
+		pp, err := sp.parse()
+		if err != nil {
+			return nil, fmt.Errorf("genpass: store %q: %w", name, err)
+		}
+		policies[name] = pp
+	}
+	pushPolicyOf := func(store string) pushPolicy {
+		if pp, ok := policies[store]; ok {
+			return pp
+		}
+		return pushPolicy{backoff: DefaultBackoff}
+	}
+
+	seen := map[string]bool{}
+	plan := make(Plan, 0, len(m.Secrets))
+	for _, s := range m.Secrets {
+		if s.Name == "" {
+			return nil, errors.New("genpass: manifest secret without a name")
+		}
+		// names are unique across stores, since they are also ledger labels
+		if seen[s.Name] {
+			return nil, fmt.Errorf("genpass: secret %q is declared more than once", s.Name)
+		}
+		seen[s.Name] = true
+
+		var maxAge time.Duration
+		if s.MaxAge != "" {
+			var err error
+			if maxAge, err = ParseAge(s.MaxAge); err != nil {
+				return nil, fmt.Errorf("genpass: secret %q: invalid max age %q", s.Name, s.MaxAge)
+			}
+		}
+		if s.Policy != "" {
+			if _, ok := LookupPolicy(s.Policy); !ok {
+				return nil, fmt.Errorf("genpass: secret %q: unknown policy %q", s.Name, s.Policy)
+			}
+		}
+		if _, err := s.Config.Generator(); err != nil {
+			return nil, fmt.Errorf("genpass: secret %q: %w", s.Name, err)
+		}
+
+		store, err := LookupStore(s.StoreName())
+		if err != nil {
+			return nil, err
+		}
+		value, err := store.Get(s.Name)
+		if errors.Is(err, ErrSecretNotFound) {
+			plan = append(plan, PlanStep{Secret: s, Action: PlanCreate, Reason: "does not exist", push: pushPolicyOf(s.StoreName())})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		step := PlanStep{Secret: s, Action: PlanSkip, push: pushPolicyOf(s.StoreName())}
+		e, tracked := current[s.Name]
+		tracked = tracked && e.Matches(string(value))
+		if tracked {
+			step.Age = now.Sub(e.Created)
+		}
+		switch {
+		case !tracked && maxAge > 0:
+			step.Action, step.Reason = PlanRotate, "age unknown"
+		case tracked && maxAge > 0 && step.Age > maxAge:
+			step.Action, step.Reason = PlanRotate, "older than "+s.MaxAge
+		case tracked && !maps.Equal(e.Tags, s.Tags):
+			step.Action, step.Reason = PlanRotate, "tags changed"
+		case !tracked:
+			step.Reason = "exists"
+		default:
+			step.Reason = "up to date"
+		}
+		plan = append(plan, step)
+	}
+	return plan, nil
+}
+
+// Apply creates and rotates the secrets of the plan one at a time, and
+// records each one in the ledger, which is saved after every secret so that an
+// interrupted apply leaves an accurate ledger. Secrets that violate their
+// policy are not stored. Apply stops at the first failure, which it returns as
+// an [*ApplyError].
+func (p Plan) Apply(ledger *Ledger) error {
+	for _, step := range p {
+		if step.Action == PlanSkip {
+			continue
+		}
+		if r := step.apply(ledger, &sync.Mutex{}); r.Err != nil {
+			return r.Err
+		}
+	}
+	return nil
+}
+
+// ApplyResult is the outcome of one step of a [Plan].
+type ApplyResult struct {
+	Step PlanStep
+	// Attempts is the number of times storing the secret was attempted. It
+	// is 0 if the secret could not be generated.
+	Attempts int
+	// Duration is the time taken by the step, including retries.
+	Duration time.Duration
+	// Err is an [*ApplyError] if the step failed.
+	Err error
+	// Ambiguous reports that the step failed after an attempt timed out.
+	// The timed-out attempt may still store the secret, so the store may
+	// hold a secret that is not recorded in the ledger.
+	Ambiguous bool
+}
+
+// ApplyReport is the result of [Plan.ApplyParallel].
+type ApplyReport struct {
+	// Results holds a result for each step that is not [PlanSkip], in the
+	// order of the plan.
+	Results []ApplyResult
+}
+
+// Failed returns the number of failed steps.
+func (r ApplyReport) Failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Ambiguous returns the number of failed steps that may still have stored
+// their secret. See [ApplyResult.Ambiguous].
+func (r ApplyReport) Ambiguous() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Ambiguous {
+			n++
+		}
+	}
+	return n
+}
+
+// Err returns the errors of all failed steps joined with [errors.Join], or
+// nil if every step succeeded.
+func (r ApplyReport) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyParallel is like [Plan.Apply], but applies up to workers steps at once
+// and continues after failures. Each push to a store follows the
+// [StorePolicy] of the store in the manifest: attempts that fail or time out
+// are retried with exponential backoff. Retries store the same secret, so an
+// attempt that timed out but eventually succeeded stores the secret recorded
+// in the ledger. A step that fails after an attempt timed out is reported as
+// [ApplyResult.Ambiguous].
+func (p Plan) ApplyParallel(ledger *Ledger, workers int) ApplyReport {
+	var steps []PlanStep
+	for _, step := range p {
+		if step.Action != PlanSkip {
+			steps = append(steps, step)
+		}
+	}
+	report := ApplyReport{Results: make([]ApplyResult, len(steps))}
+
+	var ledgerMu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(workers, 1))
+	for i, step := range steps {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			report.Results[i] = step.apply(ledger, &ledgerMu)
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// ApplyError records the step of a [Plan] that failed. It unwraps to the
+// underlying error.
+type ApplyError struct {
+	Step PlanStep
+	Err  error
+}
+
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("genpass: %s %q: %s", e.Step.Action, e.Step.Secret.Name, strings.TrimPrefix(e.Err.Error(), "genpass: "))
+}
+
+func (e *ApplyError) Unwrap() error { return e.Err }
+
+// apply generates and stores the secret of the step, and records it in the
+// ledger while holding ledgerMu.
+func (step PlanStep) apply(ledger *Ledger, ledgerMu *sync.Mutex) (r ApplyResult) {
+	r.Step = step
+	start := time.Now()
+	defer func() {
+		r.Duration = time.Since(start)
+		if r.Err != nil {
+			r.Err = &ApplyError{Step: step, Err: r.Err}
+		}
+	}()
+
+	s := step.Secret
+	g, err := s.Config.Generator()
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	secret, err := g.Generate()
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	if s.Policy != "" {
+		policy, _ := LookupPolicy(s.Policy)
+		if err := policy.Check(secret); err != nil {
+			r.Err = err
+			return r
+		}
+	}
+	store, err := LookupStore(s.StoreName())
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	backoff := step.push.backoff
+	for {
+		r.Attempts++
+		err = step.push.set(store, s.StoreName(), s.Name, []byte(secret))
+		if errors.Is(err, errPushTimeout) {
+			r.Ambiguous = true
+		}
+		if err == nil || r.Attempts > step.push.retries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Ambiguous = false
+
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+	if _, err := ledger.Remember(s.Name, secret, g.Entropy()); err != nil {
+		r.Err = err
+		return r
+	}
+	ledger.Entries[len(ledger.Entries)-1].Tags = maps.Clone(s.Tags)
+	r.Err = ledger.Save()
+	return r
+}
+
+// errPushTimeout is wrapped by the error of a push that timed out.
+var errPushTimeout = errors.New("timed out")
+
+// set stores secret under name in store, which is registered as storeName,
+// giving up after the timeout of the policy. A store that times out may still
+// store the secret later.
+func (pp pushPolicy) set(store SecretStore, storeName, name string, secret []byte) error {
+	if pp.timeout == 0 {
+		return store.Set(name, secret)
+	}
+	done := make(chan error, 1)
+	go func() { done <- store.Set(name, secret) }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(pp.timeout):
+		return &SinkError{Sink: storeName, Err: fmt.Errorf("%w after %v", errPushTimeout, pp.timeout)}
+	}
+}
@@ -0,0 +1,114 @@
+package genpass
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//go:embed wordlists/bip39_english.txt
+var bip39English string
+
+// WordlistBIP39 is the English BIP-39 wordlist of 2048 words. Every word is
+// uniquely identified by its first four letters.
+//
+// See https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki.
+var WordlistBIP39 = strings.Fields(bip39English)
+
+var bip39Index = func() map[string]int {
+	m := make(map[string]int, len(WordlistBIP39))
+	for i, w := range WordlistBIP39 {
+		m[w] = i
+	}
+	return m
+}()
+
+// ErrMnemonicChecksum is returned by [MnemonicToBytes] when the checksum of a
+// mnemonic doesn't match, usually because a word was written down wrong.
+var ErrMnemonicChecksum = errors.New("genpass: invalid mnemonic checksum")
+
+// GenerateMnemonic generates a BIP-39 mnemonic encoding bits random bits.
+// bits must be 128, 160, 192, 224, or 256, producing 12 to 24 words.
+func GenerateMnemonic(bits int) (string, error) {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return "", fmt.Errorf("genpass: mnemonic size must be 128, 160, 192, 224, or 256 bits, not %d", bits)
+	}
+	entropy, err := GenerateBytes(bits / 8)
+	if err != nil {
+		return "", err
+	}
+	return BytesToMnemonic(entropy)
+}
+
+// BytesToMnemonic encodes entropy as a BIP-39 mnemonic. The entropy must be
+// 16, 20, 24, 28, or 32 bytes long. The last word includes a checksum of
+// len(entropy)/4 bits.
+func BytesToMnemonic(entropy []byte) (string, error) {
+	n := len(entropy)
+	if n%4 != 0 || n < 16 || n > 32 {
+		return "", fmt.Errorf("genpass: mnemonic entropy must be 16, 20, 24, 28, or 32 bytes, not %d", n)
+	}
+	sum := sha256.Sum256(entropy)
+	data := append(append([]byte(nil), entropy...), sum[0])
+	total := n*8 + n/4
+
+	words := make([]string, total/11)
+	for i := range words {
+		idx := 0
+		for b := i * 11; b < i*11+11; b++ {
+			idx = idx<<1 | int(data[b/8]>>(7-b%8)&1)
+		}
+		words[i] = WordlistBIP39[idx]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToBytes decodes a BIP-39 mnemonic back to the entropy it encodes,
+// verifying its checksum. Words are separated by whitespace and may be
+// abbreviated to their first four letters.
+func MnemonicToBytes(mnemonic string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, fmt.Errorf("genpass: mnemonic must have 12, 15, 18, 21, or 24 words, not %d", len(words))
+	}
+
+	total := len(words) * 11
+	data := make([]byte, (total+7)/8)
+	for i, w := range words {
+		idx, ok := bip39Index[w]
+		if !ok {
+			idx, ok = bip39Prefix(w)
+		}
+		if !ok {
+			return nil, fmt.Errorf("genpass: word %d (%q) is not in the BIP-39 wordlist", i+1, w)
+		}
+		for j := range 11 {
+			b := i*11 + j
+			data[b/8] |= byte(idx>>(10-j)&1) << (7 - b%8)
+		}
+	}
+
+	n := total * 32 / 33 / 8
+	entropy := data[:n]
+	sum := sha256.Sum256(entropy)
+	checkBits := n / 4
+	if data[n]>>(8-checkBits) != sum[0]>>(8-checkBits) {
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// bip39Prefix looks up a word abbreviated to its first four letters.
+func bip39Prefix(w string) (int, bool) {
+	if len(w) != 4 {
+		return 0, false
+	}
+	for i, word := range WordlistBIP39 {
+		if strings.HasPrefix(word, w) {
+			return i, true
+		}
+	}
+	return 0, false
+}
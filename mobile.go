@@ -0,0 +1,52 @@
+package genpass
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsMobileSafeWord reports whether w is likely to be typed unchanged on a phone
+// keyboard. Words made only of lowercase letters pass. Words with capitals
+// (usually proper nouns, which autocorrect "fixes" the capitalization of),
+// hyphens, apostrophes, digits, or other punctuation fail, since smart
+// punctuation and autocorrect often rewrite them.
+func IsMobileSafeWord(w string) bool {
+	if w == "" {
+		return false
+	}
+	for _, r := range w {
+		if !unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// MobileSafeWords returns the words of wordlist for which [IsMobileSafeWord]
+// is true.
+func MobileSafeWords(wordlist []string) []string {
+	var words []string
+	for _, w := range wordlist {
+		if IsMobileSafeWord(w) {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// IsMobileSafeSeparator reports whether sep can be placed between words
+// without phone keyboards changing it: it must not contain quotes (turned into
+// curly quotes), double hyphens (turned into a dash), two spaces (turned into
+// ". " on some keyboards), or sentence-ending punctuation followed by a space
+// (which makes the next word start with a capital letter).
+func IsMobileSafeSeparator(sep string) bool {
+	if strings.ContainsAny(sep, "\"'`") {
+		return false
+	}
+	for _, s := range []string{"--", "  ", "...", ". ", "! ", "? "} {
+		if strings.Contains(sep, s) {
+			return false
+		}
+	}
+	return true
+}
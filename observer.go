@@ -0,0 +1,168 @@
+package genpass
+
+import (
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Observer receives events from genpass operations, for auditing, metrics, or
+// rate limiting in programs that embed the package. Events never include the
+// generated secrets themselves.
+//
+// Observers are called synchronously, possibly from several goroutines at
+// once, so they should be safe for concurrent use and return quickly.
+type Observer interface {
+	// OnGenerate is called after a password or passphrase is generated.
+	OnGenerate(GenerateEvent)
+	// OnReject is called when a candidate secret is discarded and generated
+	// again.
+	OnReject(RejectEvent)
+	// OnSinkWrite is called after secrets are written to an output: a
+	// batch, an [Emitter], or a [SecretStore].
+	OnSinkWrite(SinkWriteEvent)
+}
+
+// GenerateEvent describes a generated secret.
+type GenerateEvent struct {
+	// Passphrase reports whether the secret is a passphrase.
+	Passphrase bool
+	// Length is the number of characters in the secret.
+	Length int
+	// Entropy is the entropy of the configuration in bits.
+	Entropy float64
+}
+
+// RejectReason is the reason a candidate secret was discarded.
+type RejectReason string
+
+const (
+	// RejectMissingClass means the candidate lacked a class required with
+	// [WithRequiredClasses].
+	RejectMissingClass RejectReason = "missing-class"
+	// RejectDuplicate means the candidate was already part of a batch with
+	// [BatchConfig.Unique] set.
+	RejectDuplicate RejectReason = "duplicate"
+	// RejectDenylisted means the candidate contained a word passed to
+	// [WithDenylist].
+	RejectDenylisted RejectReason = "denylisted"
+)
+
+// RejectEvent describes a discarded candidate secret.
+type RejectEvent struct {
+	Reason RejectReason
+	// Missing lists the required classes a candidate rejected with
+	// [RejectMissingClass] lacked.
+	Missing []Class
+}
+
+// SinkWriteEvent describes secrets written to an output.
+type SinkWriteEvent struct {
+	// Sink identifies the output: "batch" for [WriteBatch], "writer" for
+	// [GenerateTo], or the name an emitter or store is registered under.
+	Sink string
+	// Name is the name of the secret, for emitters and stores.
+	Name string
+	// Count is the number of secrets written.
+	Count int
+	// Err is the error the write failed with, if any.
+	Err error
+}
+
+// ObserverFuncs adapts a set of functions to an [Observer]. Nil functions
+// ignore their events.
+type ObserverFuncs struct {
+	Generate  func(GenerateEvent)
+	Reject    func(RejectEvent)
+	SinkWrite func(SinkWriteEvent)
+}
+
+func (o ObserverFuncs) OnGenerate(e GenerateEvent) {
+	if o.Generate != nil {
+		o.Generate(e)
+	}
+}
+
+func (o ObserverFuncs) OnReject(e RejectEvent) {
+	if o.Reject != nil {
+		o.Reject(e)
+	}
+}
+
+func (o ObserverFuncs) OnSinkWrite(e SinkWriteEvent) {
+	if o.SinkWrite != nil {
+		o.SinkWrite(e)
+	}
+}
+
+// observers is read on every generated secret, so it is swapped atomically
+// rather than guarded by a lock.
+var (
+	observersMu sync.Mutex
+	observers   atomic.Pointer[[]*Observer]
+)
+
+// RegisterObserver adds an observer that is notified of all subsequent
+// events. The returned function removes it again.
+func RegisterObserver(o Observer) (unregister func()) {
+	p := &o
+	observersMu.Lock()
+	defer observersMu.Unlock()
+	var list []*Observer
+	if cur := observers.Load(); cur != nil {
+		list = slices.Clone(*cur)
+	}
+	list = append(list, p)
+	observers.Store(&list)
+
+	return func() {
+		observersMu.Lock()
+		defer observersMu.Unlock()
+		list := slices.DeleteFunc(slices.Clone(*observers.Load()), func(q *Observer) bool { return q == p })
+		observers.Store(&list)
+	}
+}
+
+// notify calls f for each registered observer.
+func notify(f func(Observer)) {
+	if list := observers.Load(); list != nil {
+		for _, o := range *list {
+			f(*o)
+		}
+	}
+}
+
+// observing reports whether any observers are registered, so that callers
+// can skip building events nobody receives.
+func observing() bool {
+	list := observers.Load()
+	return list != nil && len(*list) > 0
+}
+
+// observedEmitter reports the secrets written by an emitter to observers.
+type observedEmitter struct {
+	name string
+	Emitter
+}
+
+func (e observedEmitter) Emit(w io.Writer, s NamedSecret) error {
+	err := e.Emitter.Emit(w, s)
+	notify(func(o Observer) { o.OnSinkWrite(SinkWriteEvent{Sink: e.name, Name: s.Name, Count: 1, Err: err}) })
+	return err
+}
+
+// observedStore reports the secrets stored in a secret store to observers.
+type observedStore struct {
+	name string
+	SecretStore
+}
+
+func (s observedStore) Set(name string, secret []byte) error {
+	err := s.SecretStore.Set(name, secret)
+	if err != nil {
+		err = &SinkError{Sink: s.name, Err: err}
+	}
+	notify(func(o Observer) { o.OnSinkWrite(SinkWriteEvent{Sink: s.name, Name: name, Count: 1, Err: err}) })
+	return err
+}
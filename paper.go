@@ -0,0 +1,206 @@
+package genpass
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// PaperBackup describes a printable backup sheet for a secret, such as a
+// master passphrase or recovery key, meant to be stored offline.
+type PaperBackup struct {
+	// Title is printed at the top of the sheet. If empty, "genpass backup" is
+	// used.
+	Title string
+	// Secret is the value being backed up. It is printed in large text, split
+	// into groups of GroupSize characters, and encoded in a QR code.
+	Secret string
+	// GroupSize is the number of characters in each group of the printed
+	// secret. Zero prints the secret as is.
+	GroupSize int
+	// Created is the creation time printed on the sheet. If zero, the current
+	// time is used.
+	Created time.Time
+	// Comment is an optional line of text printed below the metadata.
+	Comment string
+}
+
+// Page geometry of paper backups, in PDF points. The sheet is US Letter; the
+// margins leave it printable on A4 as well.
+const (
+	paperWidth    = 612
+	paperHeight   = 792
+	paperMargin   = 72
+	paperTextSize = 18
+	paperQRSize   = 216
+)
+
+// WritePaperBackup writes b to w as a single-page PDF. The sheet shows the
+// secret in large monospaced text, its transcription [Checksum], a QR code of
+// the secret, and when it was created. Only printable Latin-1 text can be
+// printed, since the sheet uses the standard PDF fonts to avoid embedding any.
+func WritePaperBackup(w io.Writer, b PaperBackup) error {
+	if b.Secret == "" {
+		return errors.New("genpass: paper backup of an empty secret")
+	}
+	if b.Title == "" {
+		b.Title = "genpass backup"
+	}
+	if b.Created.IsZero() {
+		b.Created = time.Now()
+	}
+	for _, s := range []string{b.Title, b.Secret, b.Comment} {
+		if _, err := pdfString(s); err != nil {
+			return err
+		}
+	}
+	q, err := qrcode.New(b.Secret, qrcode.Medium)
+	if err != nil {
+		return fmt.Errorf("genpass: %w", err)
+	}
+
+	var c bytes.Buffer
+	y := paperHeight - paperMargin - 20
+	pdfText(&c, "F2", 20, paperMargin, y, b.Title)
+
+	y -= 28
+	meta := []string{
+		"Created: " + b.Created.Format("2006-01-02 15:04 MST"),
+		fmt.Sprintf("Length: %d characters", len([]rune(b.Secret))),
+		"Checksum: " + Checksum(b.Secret),
+	}
+	if b.Comment != "" {
+		meta = append(meta, b.Comment)
+	}
+	for _, line := range meta {
+		pdfText(&c, "F1", 11, paperMargin, y, line)
+		y -= 15
+	}
+
+	// Courier glyphs are 3/5 em wide, which fixes the number of characters
+	// that fit on a line.
+	y -= 2 * paperTextSize
+	perLine := (paperWidth - 2*paperMargin) * 5 / (3 * paperTextSize)
+	secret := Group(b.Secret, b.GroupSize, " ")
+	for _, line := range wrapText(secret, perLine) {
+		pdfText(&c, "F3", paperTextSize, paperMargin, y, line)
+		y -= paperTextSize * 3 / 2
+	}
+
+	if y-paperTextSize-paperQRSize < paperMargin {
+		return errors.New("genpass: secret is too long to fit on a paper backup")
+	}
+
+	// draw the dark modules of the QR code as filled squares, centered below
+	// the text
+	bitmap := q.Bitmap()
+	module := float64(paperQRSize) / float64(len(bitmap))
+	left := float64(paperWidth-paperQRSize) / 2
+	top := float64(y - paperTextSize)
+	for r, row := range bitmap {
+		for col, dark := range row {
+			if dark {
+				fmt.Fprintf(&c, "%.3f %.3f %.3f %.3f re\n", left+float64(col)*module, top-float64(r+1)*module, module, module)
+			}
+		}
+	}
+	c.WriteString("f\n")
+
+	pdfText(&c, "F1", 9, paperMargin, paperMargin, "Store this sheet offline. Check transcriptions against the checksum.")
+
+	return writePDF(w, c.Bytes())
+}
+
+// wrapText splits s into lines of at most width characters, breaking at
+// spaces where possible, or else after a hyphen or other word separator.
+func wrapText(s string, width int) []string {
+	var lines []string
+	chars := []rune(s)
+	for len(chars) > width {
+		cut := width
+		if i := lastIndexRune(chars[1:width+1], " "); i >= 0 {
+			cut = i + 1
+		} else if i := lastIndexRune(chars[:width], "-_.,"); i >= 0 {
+			cut = i + 1
+		}
+		lines = append(lines, strings.TrimRight(string(chars[:cut]), " "))
+		chars = []rune(strings.TrimLeft(string(chars[cut:]), " "))
+	}
+	return append(lines, string(chars))
+}
+
+// lastIndexRune returns the index of the last rune in chars that is one of
+// the runes in set, or -1.
+func lastIndexRune(chars []rune, set string) int {
+	for i := len(chars) - 1; i >= 0; i-- {
+		if strings.ContainsRune(set, chars[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// pdfText writes a line of text at x, y in the named font to a content
+// stream. The text has already been checked by pdfString.
+func pdfText(c *bytes.Buffer, font string, size, x, y int, text string) {
+	s, _ := pdfString(text)
+	fmt.Fprintf(c, "BT /%s %d Tf %d %d Td %s Tj ET\n", font, size, x, y, s)
+}
+
+// pdfString returns s as a PDF literal string in WinAnsiEncoding.
+func pdfString(s string) (string, error) {
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			return "", fmt.Errorf("genpass: paper backups can't print %q", r)
+		}
+	}
+	sb.WriteByte(')')
+	return sb.String(), nil
+}
+
+// writePDF writes a single-page PDF with the given content stream, which may
+// use the fonts F1 (Helvetica), F2 (Helvetica-Bold), and F3 (Courier-Bold).
+func writePDF(w io.Writer, content []byte) error {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Contents 4 0 R "+
+			"/Resources << /Font << /F1 5 0 R /F2 6 0 R /F3 7 0 R >> >> >>", paperWidth, paperHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
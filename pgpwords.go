@@ -0,0 +1,73 @@
+package genpass
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+var (
+	//go:embed wordlists/pgp_even.txt
+	pgpEven string
+	//go:embed wordlists/pgp_odd.txt
+	pgpOdd string
+)
+
+// PGPWordsEven and PGPWordsOdd are the two halves of the PGP word list. Bytes
+// at even positions are encoded with the two-syllable even words and bytes at
+// odd positions with the three-syllable odd words, so that swapped, repeated,
+// or dropped words can be detected.
+var (
+	PGPWordsEven = strings.Fields(pgpEven)
+	PGPWordsOdd  = strings.Fields(pgpOdd)
+)
+
+// PGPWords is an [Encoder] and [Decoder] for the PGP word list, which encodes
+// each byte as an English word, e.g. "topmost Istanbul Pluto vagabond". It is
+// designed for verifying values read aloud over the phone.
+var PGPWords pgpWords
+
+type pgpWords struct{}
+
+var pgpIndex = func() map[string]int {
+	m := make(map[string]int, 512)
+	for i, w := range PGPWordsEven {
+		m[strings.ToLower(w)] = i
+	}
+	for i, w := range PGPWordsOdd {
+		m[strings.ToLower(w)] = 256 + i
+	}
+	return m
+}()
+
+func (pgpWords) Encode(src []byte) string {
+	words := make([]string, len(src))
+	for i, b := range src {
+		if i%2 == 0 {
+			words[i] = PGPWordsEven[b]
+		} else {
+			words[i] = PGPWordsOdd[b]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func (pgpWords) Decode(s string) ([]byte, error) {
+	words := strings.Fields(s)
+	dst := make([]byte, len(words))
+	for i, w := range words {
+		j, ok := pgpIndex[strings.ToLower(w)]
+		if !ok {
+			return nil, fmt.Errorf("genpass: word %d (%q) is not in the PGP word list", i+1, w)
+		}
+		if odd := j >= 256; odd != (i%2 == 1) {
+			return nil, fmt.Errorf("genpass: word %d (%q) is out of place; a word may be missing or repeated", i+1, w)
+		}
+		dst[i] = byte(j % 256)
+	}
+	return dst, nil
+}
+
+func init() {
+	RegisterEncoder("pgpwords", PGPWords)
+}
@@ -0,0 +1,274 @@
+package genpass
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed wordlists/common_passwords.txt
+var commonPasswords string
+
+// CommonPasswords is a list of the most commonly used passwords, in
+// lowercase, for use as a [Policy] blocklist.
+var CommonPasswords = func() []string {
+	var words []string
+	for _, line := range strings.Split(commonPasswords, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words
+}()
+
+// Policy is a set of rules passwords must follow, usually taken from a
+// standard. Policies can be used both to configure a [Generator] and to check
+// existing passwords.
+type Policy struct {
+	// Name is the short name the policy is registered under.
+	Name string
+	// Description names the standard the policy implements.
+	Description string
+
+	// MinLength and MaxLength bound the number of characters. A MaxLength of
+	// 0 means there is no maximum.
+	MinLength int
+	MaxLength int
+	// Required lists classes every password must contain.
+	Required []Class
+	// RequireLetter requires at least one letter, either lower- or uppercase.
+	RequireLetter bool
+	// MinClasses is the minimum number of distinct classes a password must
+	// contain characters from. Letters outside of ASCII count as their own
+	// class.
+	MinClasses int
+	// Blocklist lists passwords that are rejected regardless of the other
+	// rules. Entries are compared case-insensitively.
+	Blocklist []string
+	// NoRepetitive rejects passwords consisting of a single repeated
+	// character or a run of consecutive characters, like "aaaaaaaa" or
+	// "12345678".
+	NoRepetitive bool
+}
+
+// ViolationCode identifies the rule broken by a [Violation].
+type ViolationCode string
+
+const (
+	ViolationTooShort      ViolationCode = "too-short"
+	ViolationTooLong       ViolationCode = "too-long"
+	ViolationMissingClass  ViolationCode = "missing-class"
+	ViolationMissingLetter ViolationCode = "missing-letter"
+	ViolationTooFewClasses ViolationCode = "too-few-classes"
+	ViolationBlocklisted   ViolationCode = "blocklisted"
+	ViolationRepetitive    ViolationCode = "repetitive"
+)
+
+// Violation describes how a password breaks a rule of a [Policy].
+type Violation struct {
+	Code ViolationCode
+	// Message is a human-readable description in the current locale.
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Message
+}
+
+// Validate checks password against the policy and returns the rules it
+// breaks, or nil if it complies.
+func (p Policy) Validate(password string) []Violation {
+	loc := CurrentLocale()
+	var violations []Violation
+	add := func(code ViolationCode, msg string) {
+		violations = append(violations, Violation{Code: code, Message: msg})
+	}
+
+	n := utf8.RuneCountInString(password)
+	if n < p.MinLength {
+		add(ViolationTooShort, loc.N(MsgViolationTooShort, big.NewInt(int64(p.MinLength))))
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		add(ViolationTooLong, loc.N(MsgViolationTooLong, big.NewInt(int64(p.MaxLength))))
+	}
+	for _, c := range p.Required {
+		if !c.Set().ContainsAny(password) {
+			add(ViolationMissingClass, fmt.Sprintf(loc.T(MsgViolationMissingClass), c))
+		}
+	}
+	if p.RequireLetter && !strings.ContainsFunc(password, unicode.IsLetter) {
+		add(ViolationMissingLetter, loc.T(MsgViolationMissingLetter))
+	}
+	if p.MinClasses > 0 {
+		if found := countClasses(password); found < p.MinClasses {
+			add(ViolationTooFewClasses, fmt.Sprintf(loc.T(MsgViolationTooFewClasses), p.MinClasses, found))
+		}
+	}
+	if slices.Contains(p.Blocklist, strings.ToLower(password)) {
+		add(ViolationBlocklisted, loc.T(MsgViolationBlocklisted))
+	}
+	if p.NoRepetitive && repetitive(password) {
+		add(ViolationRepetitive, loc.T(MsgViolationRepetitive))
+	}
+	return violations
+}
+
+// countClasses returns the number of distinct classes password contains
+// characters from. Non-ASCII letters count as one additional class.
+func countClasses(password string) int {
+	n := 0
+	for _, c := range Classes {
+		if c.Set().ContainsAny(password) {
+			n++
+		}
+	}
+	if strings.ContainsFunc(password, func(r rune) bool { return r > unicode.MaxASCII && unicode.IsLetter(r) }) {
+		n++
+	}
+	return n
+}
+
+// repetitive reports whether password is a single repeated character or a
+// run of consecutive characters in either direction.
+func repetitive(password string) bool {
+	runes := []rune(strings.ToLower(password))
+	if len(runes) < 2 {
+		return false
+	}
+	for _, step := range []rune{0, 1, -1} {
+		ok := true
+		for i := 1; i < len(runes); i++ {
+			if runes[i]-runes[i-1] != step {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Check is like [Policy.Validate], but returns the violations as a
+// [*PolicyError], or nil if there are none.
+func (p Policy) Check(password string) error {
+	if violations := p.Validate(password); len(violations) > 0 {
+		return &PolicyError{Policy: p, Violations: violations}
+	}
+	return nil
+}
+
+// Length returns the length of passwords generated for the policy: 16
+// characters, or more or less if the policy requires it.
+func (p Policy) Length() int {
+	length := max(16, p.MinLength)
+	if p.MaxLength > 0 {
+		length = min(length, p.MaxLength)
+	}
+	return length
+}
+
+// RequiredClasses returns the classes generated passwords are required to
+// contain so that they satisfy the policy.
+func (p Policy) RequiredClasses() []Class {
+	required := slices.Clone(p.Required)
+	if p.MinClasses > 0 {
+		// requiring every class satisfies any minimum
+		required = slices.Clone(Classes)
+	} else if p.RequireLetter && !slices.Contains(required, ClassLower) && !slices.Contains(required, ClassUpper) {
+		required = append(required, ClassLower)
+	}
+	return required
+}
+
+// Options returns generator options that produce passwords satisfying the
+// policy. Passwords on the blocklist are vanishingly unlikely at the generated
+// length, but callers that must guarantee compliance should still call
+// [Policy.Validate] on the result.
+func (p Policy) Options() []Option {
+	return []Option{
+		WithCharset(CharsetAll),
+		WithLength(p.Length()),
+		WithRequiredClasses(p.RequiredClasses()...),
+		WithPolicyName(p.Name),
+	}
+}
+
+var (
+	policiesMu sync.RWMutex
+	policies   = map[string]Policy{}
+)
+
+// RegisterPolicy makes a policy available by its name to [LookupPolicy].
+// Registering a policy with an existing name replaces it.
+func RegisterPolicy(p Policy) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	policies[strings.ToLower(p.Name)] = p
+}
+
+// LookupPolicy returns the policy registered under name. Names are not
+// case-sensitive.
+func LookupPolicy(name string) (Policy, bool) {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	p, ok := policies[strings.ToLower(name)]
+	return p, ok
+}
+
+// Policies returns the names of all registered policies in sorted order.
+func Policies() []string {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// The built-in policies.
+var (
+	// PolicyNIST follows NIST SP 800-63B: at least 8 characters, no
+	// composition rules, and no commonly used, repetitive, or sequential
+	// passwords.
+	PolicyNIST = Policy{
+		Name:         "nist",
+		Description:  "NIST SP 800-63B",
+		MinLength:    8,
+		Blocklist:    CommonPasswords,
+		NoRepetitive: true,
+	}
+	// PolicyPCI follows PCI DSS v4.0 requirement 8.3.6: at least 12
+	// characters containing both letters and digits.
+	PolicyPCI = Policy{
+		Name:          "pci",
+		Description:   "PCI DSS v4.0",
+		MinLength:     12,
+		Required:      []Class{ClassDigit},
+		RequireLetter: true,
+	}
+	// PolicyADComplexity follows the Active Directory "password must meet
+	// complexity requirements" setting with the default minimum length: at
+	// least 7 characters from at least 3 classes. The rule against containing
+	// the account name is not checked.
+	PolicyADComplexity = Policy{
+		Name:        "ad-complexity",
+		Description: "Active Directory complexity requirements",
+		MinLength:   7,
+		MinClasses:  3,
+	}
+)
+
+func init() {
+	RegisterPolicy(PolicyNIST)
+	RegisterPolicy(PolicyPCI)
+	RegisterPolicy(PolicyADComplexity)
+}
@@ -0,0 +1,72 @@
+package genpass
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	proquintConsonants = "bdfghjklmnprstvz"
+	proquintVowels     = "aiou"
+)
+
+// Proquint is an [Encoder] and [Decoder] for proquints, pronounceable
+// five-letter quintets that each encode 16 bits, e.g. "lusab-babad".
+//
+// Proquints are well suited to identifiers that must be read aloud or written
+// down by hand. Input with an odd number of bytes is padded with a zero byte.
+//
+// See https://arxiv.org/html/0901.4016.
+var Proquint proquint
+
+type proquint struct{}
+
+func (proquint) Encode(src []byte) string {
+	var sb strings.Builder
+	for i := 0; i < len(src); i += 2 {
+		v := uint16(src[i]) << 8
+		if i+1 < len(src) {
+			v |= uint16(src[i+1])
+		}
+		if i > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(proquintConsonants[v>>12&0xf])
+		sb.WriteByte(proquintVowels[v>>10&0x3])
+		sb.WriteByte(proquintConsonants[v>>6&0xf])
+		sb.WriteByte(proquintVowels[v>>4&0x3])
+		sb.WriteByte(proquintConsonants[v&0xf])
+	}
+	return sb.String()
+}
+
+func (proquint) Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	quints := strings.Split(strings.ToLower(s), "-")
+	dst := make([]byte, 0, len(quints)*2)
+	for _, q := range quints {
+		if len(q) != 5 {
+			return nil, fmt.Errorf("genpass: invalid proquint %q", q)
+		}
+		var v uint16
+		for i := range 5 {
+			alphabet, bits := proquintConsonants, 4
+			if i%2 == 1 {
+				alphabet, bits = proquintVowels, 2
+			}
+			j := strings.IndexByte(alphabet, q[i])
+			if j < 0 {
+				return nil, fmt.Errorf("genpass: invalid proquint %q", q)
+			}
+			v = v<<bits | uint16(j)
+		}
+		dst = append(dst, byte(v>>8), byte(v))
+	}
+	return dst, nil
+}
+
+func init() {
+	RegisterEncoder("proquint", Proquint)
+}
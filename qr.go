@@ -0,0 +1,424 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/bits"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// qrModuleSize is the size in pixels of a QR code module in images written by
+// [EncodeQR].
+const qrModuleSize = 8
+
+// EncodeQR returns a PNG image of a QR code containing s, for moving a secret
+// between machines that share no network. The code uses error correction
+// level M, which survives about 15% of the image being damaged.
+func EncodeQR(s string) ([]byte, error) {
+	q, err := qrcode.New(s, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("genpass: %w", err)
+	}
+	return q.PNG(-qrModuleSize)
+}
+
+// ErrQRDamaged is returned by [DecodeQR] when a QR code has more errors than
+// its error correction codewords can correct.
+var ErrQRDamaged = errors.New("genpass: QR code is damaged")
+
+// DecodeQR reads the text of a QR code from an image. It handles clean,
+// upright images like those produced by [EncodeQR] or other generators, not
+// photographs: the code must be axis-aligned and rendered in dark modules on a
+// light background. Misread codewords are corrected with the code's error
+// correction codewords, up to half their number in each block; with more
+// errors, [ErrQRDamaged] is returned.
+func DecodeQR(img image.Image) (string, error) {
+	grid, err := sampleQR(img)
+	if err != nil {
+		return "", err
+	}
+	size := len(grid)
+	version := (size - 17) / 4
+
+	level, mask, err := readQRFormat(grid)
+	if err != nil {
+		return "", err
+	}
+
+	function := qrFunctionModules(version)
+	raw := make([]byte, 0, qrRawCodewords(version))
+	var cur byte
+	n := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if function[y][x] || len(raw) == cap(raw) {
+					continue
+				}
+				bit := grid[y][x] != qrMask(mask, x, y)
+				cur <<= 1
+				if bit {
+					cur |= 1
+				}
+				if n++; n == 8 {
+					raw = append(raw, cur)
+					cur, n = 0, 0
+				}
+			}
+		}
+	}
+
+	data, err := deinterleaveQR(raw, version, level)
+	if err != nil {
+		return "", err
+	}
+	return parseQRData(data, version)
+}
+
+// sampleQR locates the QR code in img and returns its modules, true for dark.
+func sampleQR(img image.Image) ([][]bool, error) {
+	b := img.Bounds()
+	dark := func(x, y int) bool {
+		g := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+		return g.Y < 128
+	}
+
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X-1, b.Min.Y-1
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if dark(x, y) {
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < minX {
+		return nil, errors.New("genpass: no QR code found in image")
+	}
+
+	// the top row of the top-left finder pattern is 7 dark modules
+	run := 0
+	for x := minX; x <= maxX && dark(x, minY); x++ {
+		run++
+	}
+	module := float64(run) / 7
+	size := int(math.Round(float64(maxX-minX+1) / module))
+	if module < 1 || size < 21 || size > 177 || (size-17)%4 != 0 {
+		return nil, errors.New("genpass: no QR code found in image")
+	}
+
+	grid := make([][]bool, size)
+	for r := range grid {
+		grid[r] = make([]bool, size)
+		for c := range grid[r] {
+			grid[r][c] = dark(minX+int((float64(c)+0.5)*module), minY+int((float64(r)+0.5)*module))
+		}
+	}
+	return grid, nil
+}
+
+// QR error correction levels, in the order of the table indices below.
+const (
+	qrLevelL = iota
+	qrLevelM
+	qrLevelQ
+	qrLevelH
+)
+
+// readQRFormat reads the error correction level and mask pattern from the
+// format information next to the top-left finder pattern.
+func readQRFormat(grid [][]bool) (level, mask int, err error) {
+	read := func(x, y int) int {
+		if grid[y][x] {
+			return 1
+		}
+		return 0
+	}
+	format := 0
+	for x := range 6 {
+		format = format<<1 | read(x, 8)
+	}
+	format = format<<1 | read(7, 8)
+	format = format<<1 | read(8, 8)
+	format = format<<1 | read(8, 7)
+	for y := 5; y >= 0; y-- {
+		format = format<<1 | read(8, y)
+	}
+
+	// choose the valid format closest to what was read
+	best, bestDist := -1, 4
+	for data := range 32 {
+		rem := data
+		for range 10 {
+			rem = rem<<1 ^ (rem>>9)*0x537
+		}
+		code := (data<<10 | rem) ^ 0x5412
+		if d := bits.OnesCount(uint(code ^ format)); d < bestDist {
+			best, bestDist = data, d
+		}
+	}
+	if best < 0 {
+		return 0, 0, errors.New("genpass: unreadable QR format information")
+	}
+	level = [4]int{qrLevelM, qrLevelL, qrLevelH, qrLevelQ}[best>>3]
+	return level, best & 7, nil
+}
+
+// qrMask reports whether mask pattern mask inverts the module at (x, y).
+func qrMask(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// qrAlignmentPositions returns the row and column coordinates of the
+// alignment patterns of a version.
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	num := version/7 + 2
+	step := (version*4 + num*2 + 1) / (num*2 - 2) * 2
+	if version == 32 {
+		step = 26
+	}
+	pos := make([]int, num)
+	pos[0] = 6
+	for i, p := num-1, version*4+17-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// qrFunctionModules marks the modules of a version that don't hold data:
+// finder, timing, and alignment patterns, and format and version information.
+func qrFunctionModules(version int) [][]bool {
+	size := version*4 + 17
+	m := make([][]bool, size)
+	for i := range m {
+		m[i] = make([]bool, size)
+	}
+	fill := func(x, y, w, h int) {
+		for r := y; r < y+h; r++ {
+			for c := x; c < x+w; c++ {
+				if r >= 0 && r < size && c >= 0 && c < size {
+					m[r][c] = true
+				}
+			}
+		}
+	}
+
+	// finder patterns with separators and format information
+	fill(0, 0, 9, 9)
+	fill(size-8, 0, 8, 9)
+	fill(0, size-8, 9, 8)
+	// timing patterns
+	fill(6, 0, 1, size)
+	fill(0, 6, size, 1)
+
+	pos := qrAlignmentPositions(version)
+	for i, y := range pos {
+		for j, x := range pos {
+			if i == 0 && j == 0 || i == 0 && j == len(pos)-1 || i == len(pos)-1 && j == 0 {
+				continue
+			}
+			fill(x-2, y-2, 5, 5)
+		}
+	}
+
+	if version >= 7 {
+		fill(size-11, 0, 3, 6)
+		fill(0, size-11, 6, 3)
+	}
+	return m
+}
+
+// qrRawCodewords returns the number of codewords, data and error correction,
+// in a version.
+func qrRawCodewords(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		num := version/7 + 2
+		n -= (25*num-10)*num - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n / 8
+}
+
+// Error correction codewords per block and number of blocks, indexed by level
+// and version.
+var (
+	qrECCPerBlock = [4][41]int{
+		qrLevelL: {0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		qrLevelM: {0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		qrLevelQ: {0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		qrLevelH: {0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	qrBlocks = [4][41]int{
+		qrLevelL: {0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		qrLevelM: {0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		qrLevelQ: {0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		qrLevelH: {0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+)
+
+// deinterleaveQR splits the raw codewords into blocks, corrects each block
+// with its error correction codewords, and returns the data codewords in
+// order.
+func deinterleaveQR(raw []byte, version, level int) ([]byte, error) {
+	numBlocks := qrBlocks[level][version]
+	ecc := qrECCPerBlock[level][version]
+	shortLen := len(raw) / numBlocks
+	numShort := numBlocks - len(raw)%numBlocks
+
+	blocks := make([][]byte, numBlocks)
+	for i := range blocks {
+		n := shortLen
+		if i >= numShort {
+			n++
+		}
+		blocks[i] = make([]byte, 0, n)
+	}
+	k := 0
+	// data codewords are interleaved first; short blocks have one fewer
+	for i := range shortLen - ecc + 1 {
+		for j := range blocks {
+			if i == shortLen-ecc && j < numShort {
+				continue
+			}
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+	for range ecc {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+
+	var data []byte
+	for _, block := range blocks {
+		if _, err := rsCorrect(block, ecc); err != nil {
+			return nil, ErrQRDamaged
+		}
+		data = append(data, block[:len(block)-ecc]...)
+	}
+	return data, nil
+}
+
+// parseQRData decodes the segments of the data codewords.
+func parseQRData(data []byte, version int) (string, error) {
+	pos := 0
+	read := func(n int) (int, bool) {
+		if pos+n > len(data)*8 {
+			return 0, false
+		}
+		v := 0
+		for range n {
+			v = v<<1 | int(data[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v, true
+	}
+	countBits := func(small, medium, large int) int {
+		switch {
+		case version <= 9:
+			return small
+		case version <= 26:
+			return medium
+		default:
+			return large
+		}
+	}
+
+	const alnum = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+	var out []byte
+	for {
+		mode, ok := read(4)
+		if !ok || mode == 0 {
+			break
+		}
+		switch mode {
+		case 1: // numeric
+			n, _ := read(countBits(10, 12, 14))
+			for ; n >= 3; n -= 3 {
+				v, _ := read(10)
+				out = fmt.Appendf(out, "%03d", v)
+			}
+			if n == 2 {
+				v, _ := read(7)
+				out = fmt.Appendf(out, "%02d", v)
+			} else if n == 1 {
+				v, _ := read(4)
+				out = fmt.Appendf(out, "%d", v)
+			}
+		case 2: // alphanumeric
+			n, _ := read(countBits(9, 11, 13))
+			for ; n >= 2; n -= 2 {
+				v, _ := read(11)
+				if v/45 >= len(alnum) {
+					return "", ErrQRDamaged
+				}
+				out = append(out, alnum[v/45], alnum[v%45])
+			}
+			if n == 1 {
+				v, _ := read(6)
+				if v >= len(alnum) {
+					return "", ErrQRDamaged
+				}
+				out = append(out, alnum[v])
+			}
+		case 4: // byte
+			n, _ := read(countBits(8, 16, 16))
+			for range n {
+				v, ok := read(8)
+				if !ok {
+					return "", ErrQRDamaged
+				}
+				out = append(out, byte(v))
+			}
+		case 7: // ECI designator; the content is assumed to be UTF-8
+			v, _ := read(8)
+			if v&0x80 != 0 {
+				read(8 * bits.LeadingZeros8(^byte(v)))
+			}
+		default:
+			return "", fmt.Errorf("genpass: unsupported QR code segment mode %d", mode)
+		}
+	}
+	if !utf8.Valid(out) {
+		return "", errors.New("genpass: QR code content is not valid UTF-8")
+	}
+	return string(out), nil
+}
@@ -0,0 +1,97 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultRecoveryPattern is the default pattern for [GenerateRecoveryCodes].
+const DefaultRecoveryPattern = "xxxxx-xxxxx"
+
+// CharsetRecovery is the charset recovery codes are drawn from: lowercase
+// letters and digits without the ambiguous characters 0, 1, and l.
+const CharsetRecovery = "23456789abcdefghijkmnopqrstuvwxyz"
+
+// recoveryPositions turns a pattern into one charset per position.
+func recoveryPositions(pattern string) ([]Charset, error) {
+	random := NewCharset(CharsetRecovery)
+	var positions []Charset
+	for _, r := range pattern {
+		if r == 'x' {
+			positions = append(positions, random)
+		} else {
+			positions = append(positions, NewCharset(string(r)))
+		}
+	}
+	if len(positions) == 0 {
+		return nil, errors.New("genpass: empty recovery code pattern")
+	}
+	return positions, nil
+}
+
+// GenerateRecoveryCodes generates n distinct one-time recovery codes. Each x
+// in pattern is replaced with a random character from [CharsetRecovery]; other
+// characters are copied as-is. If pattern is empty, [DefaultRecoveryPattern]
+// is used.
+func GenerateRecoveryCodes(n int, pattern string) ([]string, error) {
+	if pattern == "" {
+		pattern = DefaultRecoveryPattern
+	}
+	positions, err := recoveryPositions(pattern)
+	if err != nil {
+		return nil, err
+	}
+	g := NewGenerator(WithPositions(positions...))
+	if p := g.Possibilities(); p.IsInt64() && p.Int64() < int64(n) {
+		return nil, fmt.Errorf("genpass: pattern %q allows only %d distinct codes", pattern, p.Int64())
+	}
+
+	codes := make([]string, 0, n)
+	seen := make(map[string]bool, n)
+	for attempts := 0; len(codes) < n; attempts++ {
+		if attempts >= maxAttempts*n {
+			return nil, fmt.Errorf("%w: could not generate %d distinct recovery codes", ErrKeyspaceExhausted, n)
+		}
+		code, err := g.Generate()
+		if err != nil {
+			return nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode makes the comparison of recovery codes insensitive to
+// case and to separators the user may or may not type.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+}
+
+// HashRecoveryCode returns a bcrypt hash of code suitable for storing on a
+// server in place of the code itself. Case, spaces, and dashes are ignored, so
+// users don't have to type codes exactly as displayed.
+func HashRecoveryCode(code string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(code)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// VerifyRecoveryCode reports whether code matches a hash returned by
+// [HashRecoveryCode].
+func VerifyRecoveryCode(hash, code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(normalizeRecoveryCode(code))) == nil
+}
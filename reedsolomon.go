@@ -0,0 +1,231 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUncorrectable is returned by [DecodeReedSolomon] when a block has more
+// errors than its parity bytes can correct.
+var ErrUncorrectable = errors.New("genpass: too many errors to correct")
+
+// rsBlockSize is the largest Reed-Solomon codeword over GF(256).
+const rsBlockSize = 255
+
+// EncodeReedSolomon adds Reed-Solomon parity to data, so that it can be
+// recovered from a damaged or misread copy with [DecodeReedSolomon]. data is
+// split into blocks of 255-parity bytes, and parity bytes are appended to
+// each block; up to parity/2 corrupted bytes can be corrected in every block.
+func EncodeReedSolomon(data []byte, parity int) ([]byte, error) {
+	if parity <= 0 || parity >= rsBlockSize {
+		return nil, fmt.Errorf("genpass: parity must be between 1 and %d bytes", rsBlockSize-1)
+	}
+	gen := rsGenerator(parity)
+	code := make([]byte, 0, len(data)+(len(data)/(rsBlockSize-parity)+1)*parity)
+	for len(data) > 0 {
+		block := data[:min(len(data), rsBlockSize-parity)]
+		data = data[len(block):]
+
+		// the parity bytes are the remainder of dividing the block, shifted
+		// by parity bytes, by the generator polynomial
+		rem := make([]byte, parity)
+		for _, c := range block {
+			factor := c ^ rem[0]
+			copy(rem, rem[1:])
+			rem[parity-1] = 0
+			for i := range rem {
+				rem[i] ^= gf256Mul(gen[i+1], factor)
+			}
+		}
+		code = append(code, block...)
+		code = append(code, rem...)
+	}
+	return code, nil
+}
+
+// DecodeReedSolomon corrects errors in data encoded with [EncodeReedSolomon]
+// using the same parity, and returns the original data and the number of
+// bytes that were corrected. It returns [ErrUncorrectable] if any block has
+// more than parity/2 errors. Bytes that were lost rather than changed can't be
+// recovered, since the block boundaries depend on the length of code.
+func DecodeReedSolomon(code []byte, parity int) ([]byte, int, error) {
+	if parity <= 0 || parity >= rsBlockSize {
+		return nil, 0, fmt.Errorf("genpass: parity must be between 1 and %d bytes", rsBlockSize-1)
+	}
+	var data []byte
+	corrected := 0
+	for len(code) > 0 {
+		block := slices.Clone(code[:min(len(code), rsBlockSize)])
+		code = code[len(block):]
+		if len(block) <= parity {
+			return nil, 0, errors.New("genpass: Reed-Solomon data is truncated")
+		}
+		n, err := rsCorrect(block, parity)
+		if err != nil {
+			return nil, 0, err
+		}
+		corrected += n
+		data = append(data, block[:len(block)-parity]...)
+	}
+	return data, corrected, nil
+}
+
+// rsGenerator returns the generator polynomial of a code with ecc error
+// correction codewords, (x - a^0)(x - a^1)...(x - a^(ecc-1)), highest degree
+// first.
+func rsGenerator(ecc int) []byte {
+	gen := []byte{1}
+	for i := range ecc {
+		next := make([]byte, len(gen)+1)
+		for j, c := range gen {
+			next[j] ^= c
+			next[j+1] ^= gf256Mul(c, gf256Exp[i])
+		}
+		gen = next
+	}
+	return gen
+}
+
+// rsCorrect corrects the errors in a block in place and returns how many
+// bytes were changed. Polynomials here are stored lowest degree first, unlike
+// blocks.
+func rsCorrect(block []byte, ecc int) (int, error) {
+	syndromes := rsSyndromes(block, ecc)
+	if !slices.ContainsFunc(syndromes, func(s byte) bool { return s != 0 }) {
+		return 0, nil
+	}
+
+	// find the error locator polynomial with Berlekamp-Massey
+	locator, prev := []byte{1}, []byte{1}
+	errs, shift, lastDiscrepancy := 0, 1, byte(1)
+	for n := range ecc {
+		d := syndromes[n]
+		for i := 1; i <= errs && i < len(locator); i++ {
+			d ^= gf256Mul(locator[i], syndromes[n-i])
+		}
+		if d == 0 {
+			shift++
+			continue
+		}
+		scale := gf256Div(d, lastDiscrepancy)
+		next := slices.Clone(locator)
+		for len(next) < len(prev)+shift {
+			next = append(next, 0)
+		}
+		for i, c := range prev {
+			next[i+shift] ^= gf256Mul(scale, c)
+		}
+		if 2*errs <= n {
+			prev, errs, lastDiscrepancy, shift = locator, n+1-errs, d, 1
+		} else {
+			shift++
+		}
+		locator = next
+	}
+	if 2*errs > ecc {
+		return 0, ErrUncorrectable
+	}
+
+	// the errors are at the inverses of the roots of the locator (Chien
+	// search), and their values follow from Forney's algorithm
+	evaluator := make([]byte, ecc)
+	for i, s := range syndromes {
+		for j, c := range locator {
+			if i+j < ecc {
+				evaluator[i+j] ^= gf256Mul(s, c)
+			}
+		}
+	}
+	found := 0
+	for i := range block {
+		x := gf256Exp[len(block)-1-i]
+		xInv := gf256Exp[255-int(gf256Log[x])]
+		if gf256Eval(locator, xInv) != 0 {
+			continue
+		}
+		var derivative byte
+		for j := 1; j < len(locator); j += 2 {
+			derivative ^= gf256Mul(locator[j], gf256Pow(xInv, j-1))
+		}
+		if derivative == 0 {
+			return 0, ErrUncorrectable
+		}
+		block[i] ^= gf256Mul(x, gf256Div(gf256Eval(evaluator, xInv), derivative))
+		found++
+	}
+	if found != errs || !rsValid(block, ecc) {
+		return 0, ErrUncorrectable
+	}
+	return found, nil
+}
+
+// rsSyndromes returns the Reed-Solomon syndromes of a block with ecc error
+// correction codewords: the block evaluated at a^0 through a^(ecc-1).
+func rsSyndromes(block []byte, ecc int) []byte {
+	syndromes := make([]byte, ecc)
+	for i := range syndromes {
+		root := gf256Exp[i]
+		for _, c := range block {
+			syndromes[i] = gf256Mul(syndromes[i], root) ^ c
+		}
+	}
+	return syndromes
+}
+
+// rsValid reports whether the Reed-Solomon syndromes of a block, with ecc
+// error correction codewords over GF(256), are all zero.
+func rsValid(block []byte, ecc int) bool {
+	return !slices.ContainsFunc(rsSyndromes(block, ecc), func(s byte) bool { return s != 0 })
+}
+
+// gf256Exp and gf256Log are exponent and logarithm tables of GF(256) with the
+// QR code polynomial x^8 + x^4 + x^3 + x^2 + 1.
+var gf256Exp, gf256Log = func() (exp [512]byte, log [256]byte) {
+	x := 1
+	for i := range 255 {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
+
+func gf256Pow(a byte, n int) byte {
+	if n == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])*n%255]
+}
+
+// gf256Eval evaluates a polynomial stored lowest degree first at x.
+func gf256Eval(poly []byte, x byte) byte {
+	var y byte
+	for i := len(poly) - 1; i >= 0; i-- {
+		y = gf256Mul(y, x) ^ poly[i]
+	}
+	return y
+}
@@ -0,0 +1,72 @@
+package genpass
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+// corrupt changes n distinct bytes of each block of code, as split by
+// [DecodeReedSolomon].
+func corrupt(rng *rand.Rand, code []byte, n int) {
+	for start := 0; start < len(code); start += rsBlockSize {
+		block := code[start:min(len(code), start+rsBlockSize)]
+		for _, i := range rng.Perm(len(block))[:n] {
+			block[i] ^= byte(rng.IntN(255) + 1)
+		}
+	}
+}
+
+func TestReedSolomonCorrects(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	data := make([]byte, 600)
+	for i := range data {
+		data[i] = byte(rng.Uint32())
+	}
+	for _, parity := range []int{2, 7, 16, 32} {
+		code, err := EncodeReedSolomon(data, parity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for errs := 0; errs <= parity/2; errs++ {
+			damaged := bytes.Clone(code)
+			corrupt(rng, damaged, errs)
+			got, corrected, err := DecodeReedSolomon(damaged, parity)
+			if err != nil {
+				t.Errorf("parity %d, %d errors per block: %v", parity, errs, err)
+				continue
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("parity %d, %d errors per block: decoded data differs", parity, errs)
+			}
+			blocks := (len(code) + rsBlockSize - 1) / rsBlockSize
+			if corrected != errs*blocks {
+				t.Errorf("parity %d, %d errors per block: corrected %d bytes, want %d", parity, errs, corrected, errs*blocks)
+			}
+		}
+	}
+}
+
+func TestReedSolomonUncorrectable(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	data := make([]byte, 200)
+	for i := range data {
+		data[i] = byte(rng.Uint32())
+	}
+	// with fewer parity bytes, a block with too many errors is often close
+	// enough to another codeword to be miscorrected rather than rejected
+	for _, parity := range []int{10, 16, 32} {
+		code, err := EncodeReedSolomon(data, parity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for errs := parity/2 + 1; errs <= parity; errs++ {
+			damaged := bytes.Clone(code)
+			corrupt(rng, damaged, errs)
+			if _, _, err := DecodeReedSolomon(damaged, parity); !errors.Is(err, ErrUncorrectable) {
+				t.Errorf("parity %d, %d errors: got %v, want %v", parity, errs, err, ErrUncorrectable)
+			}
+		}
+	}
+}
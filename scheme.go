@@ -0,0 +1,159 @@
+package genpass
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Scheme is a named way of generating secrets. Programs can register their own
+// schemes, such as corporate formats or the rules of a legacy system, with
+// [RegisterScheme]; the genpass CLI makes every registered scheme available as
+// "genpass gen <name>", with entropy reporting, JSON output, and policy
+// validation.
+type Scheme interface {
+	// Name returns the name the scheme is registered under.
+	Name() string
+	// Generate generates a secret. Schemes use the fields of cfg that apply
+	// to them and ignore the rest.
+	Generate(cfg Config) (Secret, error)
+	// Entropy returns the entropy of the secrets Generate produces for cfg, in
+	// bits.
+	Entropy(cfg Config) float64
+}
+
+// Secret is a secret generated by a [Scheme].
+type Secret struct {
+	// Value is the secret itself.
+	Value string `json:"value"`
+	// Fields holds non-secret information shown alongside the secret, such as
+	// the username a credential is for.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]Scheme{}
+)
+
+// RegisterScheme makes a scheme available by its name to [LookupScheme] and
+// [GenerateScheme]. Registering a scheme with an existing name replaces it.
+func RegisterScheme(s Scheme) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	schemes[strings.ToLower(s.Name())] = s
+}
+
+// LookupScheme returns the scheme registered under name. Names are not
+// case-sensitive.
+func LookupScheme(name string) (Scheme, bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	s, ok := schemes[strings.ToLower(name)]
+	return s, ok
+}
+
+// Schemes returns the names of all registered schemes in sorted order.
+func Schemes() []string {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GenerateScheme generates a secret with the scheme registered under name. It
+// returns an [*EntropyError] if the scheme provides less entropy than
+// cfg.MinEntropy, and reports the secret to observers like [Generator.Generate]
+// does.
+func GenerateScheme(name string, cfg Config) (Secret, error) {
+	s, ok := LookupScheme(name)
+	if !ok {
+		return Secret{}, fmt.Errorf("genpass: unknown scheme %q", name)
+	}
+	// configScheme.Entropy is 0 for an invalid configuration, which would
+	// otherwise be reported as too little entropy
+	if cs, builtin := s.(configScheme); builtin {
+		if err := cs.check(cfg); err != nil {
+			return Secret{}, err
+		}
+	}
+	e := s.Entropy(cfg)
+	if e < cfg.MinEntropy {
+		return Secret{}, &EntropyError{Entropy: e, Min: cfg.MinEntropy}
+	}
+	secret, err := s.Generate(cfg)
+	if err != nil {
+		return Secret{}, err
+	}
+	// the built-in schemes use a Generator, which has notified observers
+	// already
+	if _, builtin := s.(configScheme); !builtin && observing() {
+		e := GenerateEvent{Length: utf8.RuneCountInString(secret.Value), Entropy: e}
+		notify(func(o Observer) { o.OnGenerate(e) })
+	}
+	return secret, nil
+}
+
+// configScheme is a [Scheme] backed by a [Generator] created from the
+// configuration. adjust fills in the fields that select the scheme.
+type configScheme struct {
+	name   string
+	adjust func(*Config)
+}
+
+func (s configScheme) Name() string { return s.name }
+
+func (s configScheme) Generate(cfg Config) (Secret, error) {
+	s.adjust(&cfg)
+	g, err := cfg.Generator()
+	if err != nil {
+		return Secret{}, err
+	}
+	v, err := g.Generate()
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{Value: v}, nil
+}
+
+// check returns the error of creating a generator from the configuration, if
+// any.
+func (s configScheme) check(cfg Config) error {
+	s.adjust(&cfg)
+	_, err := cfg.Generator()
+	return err
+}
+
+func (s configScheme) Entropy(cfg Config) float64 {
+	s.adjust(&cfg)
+	opts, err := cfg.Options()
+	if err != nil {
+		return 0
+	}
+	return NewGenerator(opts...).Entropy()
+}
+
+// The built-in schemes, which generate the same secrets as a [Generator]
+// created from the configuration.
+var (
+	// SchemePassword generates passwords. Config.Words is ignored.
+	SchemePassword Scheme = configScheme{"password", func(c *Config) { c.Words = 0 }}
+	// SchemePassphrase generates passphrases of Config.Words words, or 6 if
+	// it is not set.
+	SchemePassphrase Scheme = configScheme{"passphrase", func(c *Config) {
+		if c.Words == 0 {
+			c.Words = 6
+		}
+	}}
+)
+
+func init() {
+	RegisterScheme(SchemePassword)
+	RegisterScheme(SchemePassphrase)
+}
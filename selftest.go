@@ -0,0 +1,207 @@
+package genpass
+
+import (
+	"math"
+	"slices"
+)
+
+// PositionResult holds the statistical tests for one character position of a
+// [BiasTest].
+type PositionResult struct {
+	// Position is the zero-based character position.
+	Position int
+	// Samples is the number of samples that have a character at Position.
+	Samples int
+	// ChiSquare is the chi-square statistic of the character counts at
+	// Position against a uniform distribution over the alphabet, with DF
+	// degrees of freedom. P is its p-value.
+	ChiSquare float64
+	DF        int
+	P         float64
+	// SerialCorrelation is the correlation between the characters at
+	// Position and Position+1, as indices into the sorted alphabet.
+	// SerialP is its two-sided p-value. Both are NaN for the last position.
+	SerialCorrelation float64
+	SerialP           float64
+}
+
+// Report is the result of a [BiasTest].
+type Report struct {
+	// Samples is the number of generated samples.
+	Samples int
+	// Alphabet is the set of characters seen in any sample. Uniformity is
+	// tested against this set.
+	Alphabet Charset
+	// Positions holds the results for each character position.
+	Positions []PositionResult
+}
+
+// Tests returns the number of p-values in the report.
+func (r Report) Tests() int {
+	n := 0
+	for _, p := range r.Positions {
+		n++
+		if !math.IsNaN(p.SerialP) {
+			n++
+		}
+	}
+	return n
+}
+
+// MinP returns the smallest p-value in the report.
+func (r Report) MinP() float64 {
+	minP := 1.0
+	for _, p := range r.Positions {
+		minP = min(minP, p.P)
+		if !math.IsNaN(p.SerialP) {
+			minP = min(minP, p.SerialP)
+		}
+	}
+	return minP
+}
+
+// Passed reports whether no test rejects uniformity at significance level
+// alpha. Since many tests are run, alpha is divided by the number of tests
+// (the Bonferroni correction), so that a fair generator fails with
+// probability at most alpha.
+func (r Report) Passed(alpha float64) bool {
+	return r.MinP() >= alpha/float64(max(r.Tests(), 1))
+}
+
+// BiasTest generates samples outputs with gen and tests them for bias: a
+// chi-square test of uniformity at each character position and a correlation
+// test between adjacent positions. For meaningful results, samples should be
+// at least 5 times the size of the alphabet, and preferably much more.
+//
+// Outputs that contain fixed characters, like separators, make the tests at
+// those positions fail; test generators without grouping or separators.
+func BiasTest(gen func() string, samples int) Report {
+	outputs := make([][]rune, samples)
+	var alphabet []rune
+	longest := 0
+	for i := range outputs {
+		outputs[i] = []rune(gen())
+		alphabet = append(alphabet, outputs[i]...)
+		if len(alphabet) > 1<<16 {
+			slices.Sort(alphabet)
+			alphabet = slices.Compact(alphabet)
+		}
+		longest = max(longest, len(outputs[i]))
+	}
+	set := NewCharset(string(alphabet))
+	report := Report{Samples: samples, Alphabet: set}
+
+	index := func(r rune) int {
+		i, _ := slices.BinarySearch(set.chars, r)
+		return i
+	}
+
+	k := set.Len()
+	counts := make([]int, k)
+	for pos := range longest {
+		clear(counts)
+		n := 0
+		var sx, sy, sxx, syy, sxy float64
+		pairs := 0
+		for _, out := range outputs {
+			if pos >= len(out) {
+				continue
+			}
+			x := index(out[pos])
+			counts[x]++
+			n++
+			if pos+1 < len(out) {
+				y := float64(index(out[pos+1]))
+				fx := float64(x)
+				sx += fx
+				sy += y
+				sxx += fx * fx
+				syy += y * y
+				sxy += fx * y
+				pairs++
+			}
+		}
+
+		res := PositionResult{Position: pos, Samples: n, DF: k - 1}
+		expected := float64(n) / float64(k)
+		for _, c := range counts {
+			d := float64(c) - expected
+			res.ChiSquare += d * d / expected
+		}
+		res.P = chiSquareP(res.ChiSquare, res.DF)
+
+		res.SerialCorrelation, res.SerialP = math.NaN(), math.NaN()
+		if pairs > 2 {
+			np := float64(pairs)
+			cov := sxy - sx*sy/np
+			vx := sxx - sx*sx/np
+			vy := syy - sy*sy/np
+			if vx > 0 && vy > 0 {
+				r := cov / math.Sqrt(vx*vy)
+				res.SerialCorrelation = r
+				// under independence, r*sqrt(n) is approximately standard
+				// normal
+				res.SerialP = math.Erfc(math.Abs(r) * math.Sqrt(np) / math.Sqrt2)
+			}
+		}
+		report.Positions = append(report.Positions, res)
+	}
+	return report
+}
+
+// chiSquareP returns the probability that a chi-square distributed variable
+// with df degrees of freedom is at least x.
+func chiSquareP(x float64, df int) float64 {
+	if df <= 0 {
+		return 1
+	}
+	return gammaQ(float64(df)/2, x/2)
+}
+
+// gammaQ returns the regularized upper incomplete gamma function Q(a, x),
+// using a series expansion for small x and a continued fraction otherwise.
+func gammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lg, _ := math.Lgamma(a)
+	front := math.Exp(-x + a*math.Log(x) - lg)
+
+	if x < a+1 {
+		ap, del, sum := a, 1/a, 1/a
+		for range 10000 {
+			ap++
+			del *= x / ap
+			sum += del
+			if math.Abs(del) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return max(0, 1-sum*front)
+	}
+
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 10000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < 1e-15 {
+			break
+		}
+	}
+	return front * h
+}
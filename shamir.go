@@ -0,0 +1,89 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Split splits secret into n shares using Shamir's Secret Sharing, so that
+// any k of them can be combined to recover it with [Combine], and fewer than
+// k reveal nothing about it. Each share is one byte longer than the secret:
+// the first byte identifies the share, and the rest holds its value.
+//
+// k must be at least 2, and n at least k and at most 255.
+func Split(secret []byte, k, n int) ([][]byte, error) {
+	switch {
+	case len(secret) == 0:
+		return nil, errors.New("genpass: can't split an empty secret")
+	case k < 2:
+		return nil, errors.New("genpass: threshold must be at least 2")
+	case n < k:
+		return nil, fmt.Errorf("genpass: %d shares can't meet a threshold of %d", n, k)
+	case n > 255:
+		return nil, errors.New("genpass: at most 255 shares are supported")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][0] = byte(i + 1)
+	}
+	// each byte of the secret is the constant term of its own random
+	// polynomial of degree k-1 over GF(256), and share x holds its value at x
+	coeffs, err := GenerateBytes(len(secret) * (k - 1))
+	if err != nil {
+		return nil, err
+	}
+	poly := make([]byte, k)
+	for j, b := range secret {
+		poly[0] = b
+		copy(poly[1:], coeffs[j*(k-1):])
+		for _, share := range shares {
+			share[j+1] = gf256Eval(poly, share[0])
+		}
+	}
+	clear(coeffs)
+	clear(poly)
+	return shares, nil
+}
+
+// Combine recovers a secret from shares created by [Split]. It needs at least
+// as many shares as the threshold they were split with; with fewer, it
+// returns a wrong secret rather than an error, since shares don't record the
+// threshold.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("genpass: at least 2 shares are needed")
+	}
+	size := len(shares[0])
+	if size < 2 {
+		return nil, errors.New("genpass: share is too short")
+	}
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("genpass: shares have different lengths")
+		}
+		x := share[0]
+		if x == 0 || seen[x] {
+			return nil, fmt.Errorf("genpass: invalid or duplicate share %d", x)
+		}
+		seen[x] = true
+	}
+
+	// Lagrange interpolation at x = 0, where subtraction is XOR:
+	// secret = sum of y_i * prod_{j != i} x_j / (x_j ^ x_i)
+	secret := make([]byte, size-1)
+	for i, si := range shares {
+		basis := byte(1)
+		for j, sj := range shares {
+			if i != j {
+				basis = gf256Mul(basis, gf256Div(sj[0], sj[0]^si[0]))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gf256Mul(si[b+1], basis)
+		}
+	}
+	return secret, nil
+}
@@ -0,0 +1,88 @@
+package genpass
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	for _, tc := range []struct{ k, n int }{{2, 2}, {2, 3}, {3, 5}, {5, 5}, {4, 7}} {
+		shares, err := Split(secret, tc.k, tc.n)
+		if err != nil {
+			t.Fatalf("Split(%d, %d): %v", tc.k, tc.n, err)
+		}
+		if len(shares) != tc.n {
+			t.Fatalf("Split(%d, %d) returned %d shares", tc.k, tc.n, len(shares))
+		}
+		for subset := range subsets(tc.n) {
+			picked := make([][]byte, len(subset))
+			for i, s := range subset {
+				picked[i] = shares[s]
+			}
+			got, err := Combine(picked)
+			switch {
+			case len(subset) < 2:
+				if err == nil {
+					t.Errorf("%d/%d: Combine of %v succeeded", tc.k, tc.n, subset)
+				}
+			case err != nil:
+				t.Errorf("%d/%d: Combine of %v: %v", tc.k, tc.n, subset, err)
+			case len(subset) >= tc.k && !bytes.Equal(got, secret):
+				t.Errorf("%d/%d: Combine of %v = %q, want %q", tc.k, tc.n, subset, got, secret)
+			case len(subset) < tc.k && bytes.Equal(got, secret):
+				t.Errorf("%d/%d: Combine of %v recovered the secret below the threshold", tc.k, tc.n, subset)
+			}
+		}
+	}
+}
+
+// subsets yields every non-empty subset of the indices 0 through n-1.
+func subsets(n int) func(yield func([]int) bool) {
+	return func(yield func([]int) bool) {
+		for mask := 1; mask < 1<<n; mask++ {
+			var s []int
+			for i := range n {
+				if mask&(1<<i) != 0 {
+					s = append(s, i)
+				}
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+func TestSplitInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		secret []byte
+		k, n   int
+	}{
+		{nil, 2, 3},
+		{[]byte("x"), 1, 3},
+		{[]byte("x"), 4, 3},
+		{[]byte("x"), 2, 256},
+	} {
+		if _, err := Split(tc.secret, tc.k, tc.n); err == nil {
+			t.Errorf("Split(%q, %d, %d) succeeded", tc.secret, tc.k, tc.n)
+		}
+	}
+}
+
+func TestCombineInvalid(t *testing.T) {
+	shares, err := Split([]byte("secret"), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string][][]byte{
+		"duplicate":  {shares[0], shares[0]},
+		"zero index": {append([]byte{0}, shares[0][1:]...), shares[1]},
+		"lengths":    {shares[0], shares[1][:4]},
+		"too short":  {shares[0][:1], shares[1][:1]},
+	} {
+		if _, err := Combine(tc); err == nil {
+			t.Errorf("%s: Combine succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package genpass
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// shredBufSize is the size of the writes Shred overwrites files with.
+const shredBufSize = 64 * 1024
+
+// Shred overwrites the file at path with random data, syncs it to disk,
+// renames it to a random name so the original name doesn't remain in the
+// directory, and removes it. Symlinks and other files that aren't regular files
+// are refused rather than followed.
+//
+// Shredding only reduces the remnants a file leaves on disk where data is
+// overwritten in place. On copy-on-write and journaling filesystems (btrfs,
+// ZFS, APFS, data=journal ext4) and on SSDs with wear leveling, the previous
+// contents may survive elsewhere; full-disk encryption is the only reliable
+// protection there.
+func Shred(path string) error {
+	if err := shred(path); err != nil {
+		return fmt.Errorf("genpass: shredding %s: %w", path, err)
+	}
+	return nil
+}
+
+func shred(path string) error {
+	// check the path itself before opening it, which follows symlinks, so that
+	// shredding a symlink doesn't overwrite the file it points to
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return errors.New("not a regular file")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if opened, err := f.Stat(); err != nil {
+		return err
+	} else if !os.SameFile(fi, opened) {
+		return errors.New("file was replaced while shredding")
+	}
+
+	buf := make([]byte, shredBufSize)
+	for remaining := fi.Size(); remaining > 0; {
+		n := min(remaining, int64(len(buf)))
+		if _, err := io.ReadFull(entropySource{}, buf[:n]); err != nil {
+			return entropyError(err)
+		}
+		if _, err := f.Write(buf[:n]); err != nil {
+			return err
+		}
+		remaining -= n
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	hidden, err := randomSibling(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(path, hidden); err != nil {
+		return err
+	}
+	return os.Remove(hidden)
+}
+
+// SoftDelete moves the file at path aside to a hidden name in the same
+// directory and returns that name, so that a replacement can be written to
+// path while the previous file is kept. Callers then [Shred] the previous file
+// once the replacement is safely written, or rename it back if writing fails.
+//
+// If there is no file at path, SoftDelete returns "" and no error.
+func SoftDelete(path string) (string, error) {
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	hidden, err := randomSibling(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(path, hidden); err != nil {
+		return "", fmt.Errorf("genpass: soft-deleting %s: %w", path, err)
+	}
+	return hidden, nil
+}
+
+// randomSibling returns a random hidden file name in the directory of path.
+func randomSibling(path string) (string, error) {
+	b, err := GenerateBytes(8)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), ".genpass-"+hex.EncodeToString(b)), nil
+}
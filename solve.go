@@ -0,0 +1,185 @@
+package genpass
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Constraints describe the password rules of a site.
+type Constraints struct {
+	// MaxLength is the maximum number of characters the site accepts.
+	MaxLength int
+	// Required lists the classes that must appear at least once.
+	Required []Class
+	// Allowed lists the classes the site accepts. If empty, all classes are
+	// allowed.
+	Allowed []Class
+	// Specials is the set of special characters the site accepts. If empty,
+	// [CharsetSpecial] is used.
+	Specials string
+}
+
+// Solution is the password format chosen by [Solve].
+type Solution struct {
+	Charset  string
+	Length   int
+	Required []Class
+	// Specials is the set of special characters that count as [ClassSpecial]
+	// if it is required, when it differs from [CharsetSpecial].
+	Specials string
+	Entropy  float64
+}
+
+// Options returns the generator options for the solution.
+func (s Solution) Options() []Option {
+	opts := []Option{
+		WithCharset(s.Charset),
+		WithLength(s.Length),
+		WithRequiredClasses(s.Required...),
+	}
+	if s.Specials != "" {
+		opts = append(opts, WithSpecials(s.Specials))
+	}
+	return opts
+}
+
+// Solve finds the password format with the most entropy that satisfies c.
+// Every available position is used for random characters; no positions are
+// spent on separators or grouping.
+func Solve(c Constraints) (Solution, error) {
+	allowed := c.Allowed
+	if len(allowed) == 0 {
+		allowed = Classes
+	}
+	c.Required = uniqueClasses(c.Required)
+	for _, r := range c.Required {
+		if !slices.Contains(allowed, r) {
+			return Solution{}, fmt.Errorf("genpass: required class %s is not allowed", r)
+		}
+	}
+	if c.MaxLength < len(c.Required) {
+		return Solution{}, ErrUnsatisfiable
+	}
+
+	specials := c.Specials
+	if specials == "" {
+		specials = CharsetSpecial
+	}
+
+	var best Solution
+	// try every combination of allowed classes that includes the required ones
+	for mask := range 1 << len(allowed) {
+		var sb strings.Builder
+		ok := true
+		for i, class := range allowed {
+			included := mask&(1<<i) != 0
+			if !included {
+				if slices.Contains(c.Required, class) {
+					ok = false
+					break
+				}
+				continue
+			}
+			if class == ClassSpecial {
+				sb.WriteString(specials)
+			} else {
+				sb.WriteString(class.Charset())
+			}
+		}
+		if !ok || sb.Len() == 0 {
+			continue
+		}
+
+		s := Solution{
+			Charset:  NormalizeCharset(sb.String()),
+			Length:   c.MaxLength,
+			Required: c.Required,
+			Specials: c.Specials,
+		}
+		s.Entropy = NewGenerator(s.Options()...).Entropy()
+		if s.Entropy > best.Entropy {
+			best = s
+		}
+	}
+	if best.Charset == "" {
+		return Solution{}, ErrUnsatisfiable
+	}
+	return best, nil
+}
+
+// PassphraseConstraints describe the length limit of a site that accepts
+// passphrases.
+type PassphraseConstraints struct {
+	// MaxChars is the maximum number of characters the site accepts,
+	// including separators.
+	MaxChars int
+	// Wordlist is the wordlist to choose words from. If nil, [WordlistEFF]
+	// is used.
+	Wordlist []string
+	// Separator is placed between words.
+	Separator string
+	// Extra is the number of characters added by transforms, e.g. 1 for
+	// [InsertDigit].
+	Extra int
+}
+
+// PassphraseSolution is the passphrase format chosen by [SolvePassphrase].
+type PassphraseSolution struct {
+	// Wordlist contains the words no longer than MaxWordLen.
+	Wordlist   []string
+	Words      int
+	MaxWordLen int
+	Entropy    float64
+}
+
+// Options returns the generator options for the solution. The separator and
+// any transforms must be added separately.
+func (s PassphraseSolution) Options() []Option {
+	return []Option{WithWords(s.Wordlist, s.Words)}
+}
+
+// SolvePassphrase finds the passphrase format with the most entropy that fits
+// in c.MaxChars characters. It trades the number of words against their
+// length: only words of up to some length are used, and as many of them as
+// fit even if every word has the maximum length.
+func SolvePassphrase(c PassphraseConstraints) (PassphraseSolution, error) {
+	wordlist := c.Wordlist
+	if wordlist == nil {
+		wordlist = WordlistEFF
+	}
+	sepLen := utf8.RuneCountInString(c.Separator)
+
+	byLen := map[int][]string{}
+	longest := 0
+	for _, w := range wordlist {
+		n := utf8.RuneCountInString(w)
+		byLen[n] = append(byLen[n], w)
+		longest = max(longest, n)
+	}
+
+	var best PassphraseSolution
+	var words []string
+	for maxLen := 1; maxLen <= longest; maxLen++ {
+		words = append(words, byLen[maxLen]...)
+		count := (c.MaxChars - c.Extra + sepLen) / (maxLen + sepLen)
+		if len(words) < 2 || count < 1 {
+			continue
+		}
+		e := math.Log2(float64(len(words))) * float64(count)
+		if e > best.Entropy {
+			best = PassphraseSolution{
+				Wordlist:   slices.Clone(words),
+				Words:      count,
+				MaxWordLen: maxLen,
+				Entropy:    e,
+			}
+		}
+	}
+	if best.Words == 0 {
+		return PassphraseSolution{}, ErrUnsatisfiable
+	}
+	return best, nil
+}
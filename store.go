@@ -0,0 +1,72 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// SecretStore is a place to keep secrets by name, such as the operating
+// system's credential store.
+type SecretStore interface {
+	// Set stores secret under name, replacing any existing secret.
+	Set(name string, secret []byte) error
+	// Get returns the secret stored under name, or an error wrapping
+	// [ErrSecretNotFound] if there is none.
+	Get(name string) ([]byte, error)
+	// Delete removes the secret stored under name.
+	Delete(name string) error
+}
+
+// ErrSecretNotFound is returned by [SecretStore.Get] when no secret is stored
+// under the requested name.
+var ErrSecretNotFound = errors.New("genpass: secret not found")
+
+// StoreService is the service name secrets are stored under in the system
+// credential store.
+const StoreService = "genpass"
+
+var (
+	storesMu sync.RWMutex
+	stores   = map[string]SecretStore{}
+)
+
+// RegisterStore makes a secret store available by name to [LookupStore].
+// The system credential store is registered as "system" on supported
+// platforms.
+func RegisterStore(name string, store SecretStore) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	stores[name] = store
+}
+
+// LookupStore returns the secret store registered under name.
+func LookupStore(name string) (SecretStore, error) {
+	storesMu.RLock()
+	defer storesMu.RUnlock()
+	s, ok := stores[name]
+	if !ok {
+		return nil, fmt.Errorf("genpass: unknown secret store %q", name)
+	}
+	return observedStore{name, s}, nil
+}
+
+// Stores returns the names of the registered secret stores in sorted order.
+func Stores() []string {
+	storesMu.RLock()
+	defer storesMu.RUnlock()
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SystemStore returns the operating system's credential store: the Keychain
+// on macOS, the Credential Manager on Windows, and the freedesktop Secret
+// Service (through secret-tool) on other Unix systems.
+func SystemStore() (SecretStore, error) {
+	return LookupStore("system")
+}
@@ -0,0 +1,65 @@
+package genpass
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// keychainStore stores secrets in the macOS Keychain using the security
+// command. Secrets are passed on stdin so they never appear in process
+// arguments.
+type keychainStore struct{}
+
+func (keychainStore) checkName(name string) error {
+	if name == "" || strings.ContainsAny(name, "\"\\\n") {
+		return fmt.Errorf("genpass: invalid secret name %q", name)
+	}
+	return nil
+}
+
+func (s keychainStore) Set(name string, secret []byte) error {
+	if err := s.checkName(name); err != nil {
+		return err
+	}
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a \"%s\" -X %s\n", StoreService, name, hex.EncodeToString(secret)))
+	if out, err := cmd.CombinedOutput(); err != nil || len(bytes.TrimSpace(out)) > 0 {
+		return fmt.Errorf("genpass: keychain: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s keychainStore) Get(name string) ([]byte, error) {
+	if err := s.checkName(name); err != nil {
+		return nil, err
+	}
+	out, err := exec.Command("security", "find-generic-password", "-s", StoreService, "-a", name, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+		return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("genpass: keychain: %w", err)
+	}
+	return bytes.TrimSuffix(out, []byte("\n")), nil
+}
+
+func (s keychainStore) Delete(name string) error {
+	if err := s.checkName(name); err != nil {
+		return err
+	}
+	err := exec.Command("security", "delete-generic-password", "-s", StoreService, "-a", name).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+		return fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return err
+}
+
+func init() {
+	RegisterStore("system", keychainStore{})
+}
@@ -0,0 +1,50 @@
+//go:build unix && !darwin
+
+package genpass
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceStore stores secrets with the freedesktop Secret Service (e.g.
+// GNOME Keyring or KWallet) using the secret-tool command. Secrets are passed
+// on stdin so they never appear in process arguments.
+type secretServiceStore struct{}
+
+func (secretServiceStore) attrs(name string) []string {
+	return []string{"service", StoreService, "account", name}
+}
+
+func (s secretServiceStore) Set(name string, secret []byte) error {
+	args := append([]string{"store", "--label=genpass: " + name}, s.attrs(name)...)
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = bytes.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("genpass: secret service: %s", strings.TrimSpace(string(out)+" "+err.Error()))
+	}
+	return nil
+}
+
+func (s secretServiceStore) Get(name string) ([]byte, error) {
+	out, err := exec.Command("secret-tool", append([]string{"lookup"}, s.attrs(name)...)...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("genpass: secret service: %w", err)
+	}
+	return out, nil
+}
+
+func (s secretServiceStore) Delete(name string) error {
+	return exec.Command("secret-tool", append([]string{"clear"}, s.attrs(name)...)...).Run()
+}
+
+func init() {
+	RegisterStore("system", secretServiceStore{})
+}
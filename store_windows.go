@@ -0,0 +1,109 @@
+package genpass
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential mirrors the Win32 CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialStore stores secrets in the Windows Credential Manager as generic
+// credentials named "genpass:<name>".
+type credentialStore struct{}
+
+func (credentialStore) target(name string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(StoreService + ":" + name)
+}
+
+func (s credentialStore) Set(name string, secret []byte) error {
+	target, err := s.target(name)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(secret)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(secret) > 0 {
+		cred.CredentialBlob = &secret[0]
+	}
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return fmt.Errorf("genpass: credential manager: %w", err)
+	}
+	return nil
+}
+
+func (s credentialStore) Get(name string) ([]byte, error) {
+	target, err := s.target(name)
+	if err != nil {
+		return nil, err
+	}
+	var pcred *credential
+	r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&pcred)))
+	if r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+		return nil, fmt.Errorf("genpass: credential manager: %w", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(pcred)))
+
+	secret := make([]byte, pcred.CredentialBlobSize)
+	copy(secret, unsafe.Slice(pcred.CredentialBlob, pcred.CredentialBlobSize))
+	return secret, nil
+}
+
+func (s credentialStore) Delete(name string) error {
+	target, err := s.target(name)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+		return fmt.Errorf("genpass: credential manager: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	RegisterStore("system", credentialStore{})
+}
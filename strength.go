@@ -0,0 +1,121 @@
+package genpass
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// Strength is a coarse rating of a password's entropy.
+type Strength int
+
+const (
+	StrengthVeryWeak Strength = iota
+	StrengthWeak
+	StrengthFair
+	StrengthStrong
+	StrengthVeryStrong
+)
+
+const (
+	minEntropyWeak       = 28.0
+	minEntropyFair       = 56.0
+	minEntropyStrong     = 84.0
+	minEntropyVeryStrong = 128.0
+)
+
+var strengthMessages = [...]Message{
+	StrengthVeryWeak:   MsgStrengthVeryWeak,
+	StrengthWeak:       MsgStrengthWeak,
+	StrengthFair:       MsgStrengthFair,
+	StrengthStrong:     MsgStrengthStrong,
+	StrengthVeryStrong: MsgStrengthVeryStrong,
+}
+
+// StrengthScale holds the minimum entropy, in bits, of each [Strength] above
+// [StrengthVeryWeak].
+type StrengthScale struct {
+	Weak       float64
+	Fair       float64
+	Strong     float64
+	VeryStrong float64
+}
+
+// DefaultStrengthScale is the scale used by [StrengthOf] unless another is set
+// with [SetStrengthScale].
+var DefaultStrengthScale = StrengthScale{
+	Weak:       minEntropyWeak,
+	Fair:       minEntropyFair,
+	Strong:     minEntropyStrong,
+	VeryStrong: minEntropyVeryStrong,
+}
+
+var (
+	strengthScaleMu sync.RWMutex
+	strengthScale   = DefaultStrengthScale
+)
+
+// SetStrengthScale sets the scale used by [StrengthOf], e.g. to match an
+// organization's password policy. The thresholds must be increasing.
+func SetStrengthScale(s StrengthScale) error {
+	if !(0 <= s.Weak && s.Weak < s.Fair && s.Fair < s.Strong && s.Strong < s.VeryStrong) {
+		return errors.New("genpass: strength thresholds must be increasing")
+	}
+	strengthScaleMu.Lock()
+	defer strengthScaleMu.Unlock()
+	strengthScale = s
+	return nil
+}
+
+// CurrentStrengthScale returns the scale set with [SetStrengthScale].
+func CurrentStrengthScale() StrengthScale {
+	strengthScaleMu.RLock()
+	defer strengthScaleMu.RUnlock()
+	return strengthScale
+}
+
+// Of classifies an entropy value, in bits, into a [Strength] on the scale.
+func (s StrengthScale) Of(entropy float64) Strength {
+	switch {
+	case entropy >= s.VeryStrong:
+		return StrengthVeryStrong
+	case entropy >= s.Strong:
+		return StrengthStrong
+	case entropy >= s.Fair:
+		return StrengthFair
+	case entropy >= s.Weak:
+		return StrengthWeak
+	default:
+		return StrengthVeryWeak
+	}
+}
+
+// StrengthOf classifies an entropy value, in bits, into a [Strength] using the
+// current scale (see [SetStrengthScale]).
+func StrengthOf(entropy float64) Strength {
+	return CurrentStrengthScale().Of(entropy)
+}
+
+// String returns the name of the strength in the current locale, e.g.
+// "very strong".
+func (s Strength) String() string {
+	return CurrentLocale().T(strengthMessages[s])
+}
+
+// LengthForEntropy returns the minimum number of characters drawn uniformly
+// from a charset of charsetLen characters, or words from a wordlist of
+// charsetLen words, that provides at least bits of entropy. It returns 0 if
+// bits isn't positive or charsetLen is less than 2, since no length suffices
+// then.
+func LengthForEntropy(charsetLen int, bits float64) int {
+	if bits <= 0 || charsetLen < 2 {
+		return 0
+	}
+	per := math.Log2(float64(charsetLen))
+	n := int(math.Ceil(bits / per))
+	// correct for rounding errors when bits is a multiple of per
+	if float64(n-1)*per >= bits {
+		n--
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+// Package tmplfunc provides template functions that generate secrets while a
+// template is rendered, for configuration tools built on text/template or
+// html/template:
+//
+//	t := template.New("config").Funcs(tmplfunc.FuncMap())
+//
+// The functions are:
+//
+//	genpass LENGTH [CHARSET]     a password; CHARSET is a charset expression
+//	                             like "alpha+num" (default "all")
+//	genpassphrase WORDS [SEP]    a passphrase from the EFF wordlist (default
+//	                             separator "-")
+//	genbytes N [ENCODING]        N random bytes in an encoding registered with
+//	                             genpass (default "hex")
+//
+// For example:
+//
+//	password: {{ genpass 24 "alpha+num" }}
+//	secret_key: {{ genbytes 32 "base64" }}
+//
+// Every call generates a new secret, so a template that uses a value twice
+// should store it in a variable first.
+package tmplfunc
+
+import (
+	"errors"
+	"text/template"
+
+	"github.com/calico32/genpass"
+)
+
+// FuncMap returns the template functions. The result can be passed to
+// Funcs of text/template directly, or converted to the FuncMap type of
+// html/template.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"genpass":       generate,
+		"genpassphrase": generatePassphrase,
+		"genbytes":      generateBytes,
+	}
+}
+
+func generate(length int, charset ...string) (string, error) {
+	if len(charset) > 1 {
+		return "", errors.New("tmplfunc: too many arguments")
+	}
+	config := genpass.Config{Length: length}
+	if len(charset) == 1 {
+		config.Charset = charset[0]
+	}
+	return generateConfig(config)
+}
+
+func generatePassphrase(words int, sep ...string) (string, error) {
+	if len(sep) > 1 {
+		return "", errors.New("tmplfunc: too many arguments")
+	}
+	separator := "-"
+	if len(sep) == 1 {
+		separator = sep[0]
+	}
+	if words <= 0 {
+		return "", errors.New("tmplfunc: number of words must be positive")
+	}
+	return generateConfig(genpass.Config{Words: words, Separator: &separator})
+}
+
+func generateBytes(n int, encoding ...string) (string, error) {
+	if len(encoding) > 1 {
+		return "", errors.New("tmplfunc: too many arguments")
+	}
+	name := "hex"
+	if len(encoding) == 1 {
+		name = encoding[0]
+	}
+	if n <= 0 {
+		return "", errors.New("tmplfunc: number of bytes must be positive")
+	}
+	b, err := genpass.GenerateBytes(n)
+	if err != nil {
+		return "", err
+	}
+	return genpass.EncodeBytes(name, b)
+}
+
+func generateConfig(config genpass.Config) (string, error) {
+	g, err := config.Generator()
+	if err != nil {
+		return "", err
+	}
+	return g.Generate()
+}
@@ -0,0 +1,188 @@
+package genpass
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rand is a source of uniformly distributed random integers.
+type Rand interface {
+	// Intn returns a uniformly random integer in [0, n).
+	Intn(n int) (int, error)
+}
+
+// Transform modifies the words of a generated passphrase, e.g. to satisfy
+// sites that require mixed character classes.
+type Transform interface {
+	// Apply modifies words in place, using rand for any random choices.
+	Apply(words []string, rand Rand) error
+	// Entropy returns the number of bits of entropy added by the random
+	// choices of the transform when applied to count words drawn from
+	// wordlist.
+	Entropy(wordlist []string, count int) float64
+}
+
+// WeightedTransform is implemented by transforms whose entropy depends on
+// which words are chosen, so that it can be computed for passphrases with
+// [WithWeights].
+type WeightedTransform interface {
+	Transform
+	// WeightedEntropy is like Entropy, but words are drawn from wordlist with
+	// probability proportional to weights.
+	WeightedEntropy(wordlist []string, weights []int, count int) float64
+}
+
+// Capitalize is a [Transform] that uppercases the first letter of every word.
+// It adds no entropy.
+var Capitalize Transform = capitalize{}
+
+type capitalize struct{}
+
+func (capitalize) Apply(words []string, _ Rand) error {
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return nil
+}
+
+func (capitalize) Entropy([]string, int) float64 { return 0 }
+
+func (capitalize) String() string { return "capitalize" }
+
+func (capitalize) describe([]string, []int, int) string { return "no random choices" }
+
+// InsertDigit is a [Transform] that appends a random digit to a randomly chosen
+// word.
+var InsertDigit Transform = insertDigit{}
+
+type insertDigit struct{}
+
+func (insertDigit) Apply(words []string, rand Rand) error {
+	if len(words) == 0 {
+		return nil
+	}
+	i, err := rand.Intn(len(words))
+	if err != nil {
+		return err
+	}
+	d, err := rand.Intn(10)
+	if err != nil {
+		return err
+	}
+	words[i] += strconv.Itoa(d)
+	return nil
+}
+
+func (insertDigit) Entropy(_ []string, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.Log2(10) + math.Log2(float64(count))
+}
+
+func (insertDigit) String() string { return "insert-digit" }
+
+func (insertDigit) describe(_ []string, _ []int, count int) string {
+	return fmt.Sprintf("log2(10) digits + log2(%d) words", count)
+}
+
+var leetTable = map[rune]rune{
+	'a': '4', 'A': '4',
+	'e': '3', 'E': '3',
+	'i': '1', 'I': '1',
+	'o': '0', 'O': '0',
+	's': '5', 'S': '5',
+	't': '7', 'T': '7',
+}
+
+// leetPrecision is the resolution of the probability used by LeetRandom.
+const leetPrecision = 1 << 16
+
+// LeetRandom returns a [Transform] that replaces each letter that has a
+// leet-speak equivalent (a→4, e→3, i→1, o→0, s→5, t→7) with probability p.
+func LeetRandom(p float64) Transform {
+	return leetRandom{threshold: int(math.Round(min(max(p, 0), 1) * leetPrecision))}
+}
+
+type leetRandom struct {
+	threshold int
+}
+
+func (l leetRandom) Apply(words []string, rand Rand) error {
+	for i, w := range words {
+		var sb strings.Builder
+		for _, r := range w {
+			if sub, ok := leetTable[r]; ok {
+				n, err := rand.Intn(leetPrecision)
+				if err != nil {
+					return err
+				}
+				if n < l.threshold {
+					r = sub
+				}
+			}
+			sb.WriteRune(r)
+		}
+		words[i] = sb.String()
+	}
+	return nil
+}
+
+// Entropy returns the expected entropy of the substitutions, which is the
+// binary entropy of p for every eligible letter, averaged over the wordlist.
+func (l leetRandom) Entropy(wordlist []string, count int) float64 {
+	return l.WeightedEntropy(wordlist, nil, count)
+}
+
+// WeightedEntropy is like Entropy, but averages the eligible letters over
+// words weighted by how likely they are to be chosen.
+func (l leetRandom) WeightedEntropy(wordlist []string, weights []int, count int) float64 {
+	return l.eligible(wordlist, weights) * float64(count) * l.binaryEntropy()
+}
+
+func (l leetRandom) String() string {
+	return fmt.Sprintf("leet (p=%.2f)", float64(l.threshold)/leetPrecision)
+}
+
+func (l leetRandom) describe(wordlist []string, weights []int, count int) string {
+	return fmt.Sprintf("%d words × %.2f eligible letters × %.2f bits per letter", count, l.eligible(wordlist, weights), l.binaryEntropy())
+}
+
+// binaryEntropy returns the entropy of the choice to substitute a letter.
+func (l leetRandom) binaryEntropy() float64 {
+	p := float64(l.threshold) / leetPrecision
+	if p == 0 || p == 1 {
+		return 0
+	}
+	return -p*math.Log2(p) - (1-p)*math.Log2(1-p)
+}
+
+// eligible returns the expected number of letters with a leet-speak
+// equivalent in a word drawn from wordlist, uniformly or with weights.
+func (l leetRandom) eligible(wordlist []string, weights []int) float64 {
+	total, sum := 0.0, 0.0
+	for i, w := range wordlist {
+		weight := 1.0
+		if weights != nil {
+			weight = float64(weights[i])
+		}
+		for _, r := range w {
+			// equivalent to a lookup in leetTable, which is too slow for
+			// every letter of a wordlist
+			switch r {
+			case 'a', 'A', 'e', 'E', 'i', 'I', 'o', 'O', 's', 'S', 't', 'T':
+				sum += weight
+			}
+		}
+		total += weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
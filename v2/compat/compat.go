@@ -0,0 +1,43 @@
+// Package compat provides the free functions of version 1 of genpass on top
+// of the version 2 API, so that code can move to the v2 module before every
+// call site is rewritten around a Generator.
+package compat
+
+import (
+	"context"
+	"io"
+
+	v1 "github.com/calico32/genpass"
+	"github.com/calico32/genpass/v2"
+)
+
+// Generate generates a random password of the specified length using the
+// given charset. Like the version 1 function, it panics if the arguments are
+// invalid or the system's random number generator fails.
+func Generate(length int, charset string) string {
+	g, err := genpass.New(genpass.WithCharset(genpass.NewCharset(charset)), genpass.WithLength(length))
+	if err != nil {
+		panic(err)
+	}
+	s, err := g.Generate(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// GenerateTo writes a random password of the specified length using the given
+// charset to w, without holding it in memory.
+func GenerateTo(w io.Writer, charset string, length int) error {
+	return v1.GenerateTo(w, charset, length)
+}
+
+// GenerateBytes returns n cryptographically secure random bytes.
+func GenerateBytes(n int) ([]byte, error) {
+	return v1.GenerateBytes(n)
+}
+
+// NormalizeCharset removes duplicates from charset and sorts it.
+func NormalizeCharset(charset string) string {
+	return genpass.NewCharset(charset).String()
+}
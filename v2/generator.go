@@ -0,0 +1,178 @@
+package genpass
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	v1 "github.com/calico32/genpass"
+)
+
+// Generator generates passwords or passphrases according to a set of options.
+// It is safe for concurrent use.
+type Generator struct {
+	gen    *v1.Generator
+	policy *Policy
+}
+
+// Option configures a [Generator].
+type Option func(*config) error
+
+type config struct {
+	opts   []v1.Option
+	policy *Policy
+}
+
+// New creates a Generator with the given options and checks that it can
+// generate secrets. By default, it generates 16-character passwords from
+// [CharsetAll].
+func New(opts ...Option) (*Generator, error) {
+	var c config
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, err
+		}
+	}
+	gen := v1.NewGenerator(c.opts...)
+	if err := gen.Validate(); err != nil {
+		return nil, err
+	}
+	return &Generator{gen: gen, policy: c.policy}, nil
+}
+
+// WithCharset sets the characters of passwords.
+func WithCharset(c Charset) Option {
+	return func(cfg *config) error {
+		if c.Len() == 0 {
+			return errors.New("genpass: empty charset")
+		}
+		cfg.opts = append(cfg.opts, v1.WithCharset(c.String()))
+		return nil
+	}
+}
+
+// WithLength sets the number of characters of passwords.
+func WithLength(n int) Option {
+	return func(cfg *config) error {
+		if n <= 0 {
+			return errors.New("genpass: length must be positive")
+		}
+		cfg.opts = append(cfg.opts, v1.WithLength(n))
+		return nil
+	}
+}
+
+// WithWords generates passphrases of n words from wordlist instead of
+// passwords.
+func WithWords(wordlist []string, n int) Option {
+	return func(cfg *config) error {
+		if n <= 0 {
+			return errors.New("genpass: number of words must be positive")
+		}
+		cfg.opts = append(cfg.opts, v1.WithWords(wordlist, n))
+		return nil
+	}
+}
+
+// WithSeparator sets the separator between passphrase words.
+func WithSeparator(sep string) Option {
+	return func(cfg *config) error {
+		cfg.opts = append(cfg.opts, v1.WithSeparator(sep))
+		return nil
+	}
+}
+
+// WithTransforms applies transforms to the words of passphrases.
+func WithTransforms(transforms ...Transform) Option {
+	return func(cfg *config) error {
+		cfg.opts = append(cfg.opts, v1.WithTransforms(transforms...))
+		return nil
+	}
+}
+
+// WithRequired requires passwords to contain a character from each class.
+func WithRequired(classes ...Class) Option {
+	return func(cfg *config) error {
+		cfg.opts = append(cfg.opts, v1.WithRequiredClasses(classes...))
+		return nil
+	}
+}
+
+// WithDenylist rejects secrets containing any of words, ignoring case and
+// leet-speak substitutions.
+func WithDenylist(words []string) Option {
+	return func(cfg *config) error {
+		cfg.opts = append(cfg.opts, v1.WithDenylist(words))
+		return nil
+	}
+}
+
+// WithMinEntropy makes [New] fail if the configuration provides fewer than
+// bits of entropy.
+func WithMinEntropy(bits float64) Option {
+	return func(cfg *config) error {
+		cfg.opts = append(cfg.opts, v1.WithMinEntropy(bits))
+		return nil
+	}
+}
+
+// WithPolicy makes every generated secret satisfy p. Passwords use the length
+// and classes the policy requires, unless set with other options after this
+// one, and secrets that violate it anyway are reported as errors matching
+// [ErrPolicyViolation].
+func WithPolicy(p Policy) Option {
+	return func(cfg *config) error {
+		cfg.opts = append(cfg.opts, p.Options()...)
+		cfg.policy = &p
+		return nil
+	}
+}
+
+// Generate generates a password or passphrase. ctx is only checked before
+// generation starts: generating a single secret can't be interrupted, but is
+// bounded by the number of attempts to satisfy the requirements.
+func (g *Generator) Generate(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	s, err := g.gen.Generate()
+	if err != nil {
+		return "", err
+	}
+	if g.policy != nil {
+		if err := g.policy.Check(s); err != nil {
+			return "", err
+		}
+	}
+	return s, nil
+}
+
+// GenerateN generates n secrets. It stops early and returns the context's
+// error if ctx is canceled.
+func (g *Generator) GenerateN(ctx context.Context, n int) ([]string, error) {
+	secrets := make([]string, n)
+	for i := range secrets {
+		s, err := g.Generate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = s
+	}
+	return secrets, nil
+}
+
+// Entropy returns the entropy of generated secrets in bits.
+func (g *Generator) Entropy() float64 {
+	return g.gen.Entropy()
+}
+
+// Strength returns the strength rating of generated secrets.
+func (g *Generator) Strength() Strength {
+	return v1.StrengthOf(g.gen.Entropy())
+}
+
+// Possibilities returns the number of distinct secrets the generator can
+// produce.
+func (g *Generator) Possibilities() *big.Int {
+	return g.gen.Possibilities()
+}
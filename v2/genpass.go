@@ -0,0 +1,68 @@
+// Package genpass generates random passwords and passphrases.
+//
+// Version 2 of the package is built around [Generator]: it is configured
+// once with options that take typed values like [Charset] and report invalid
+// arguments as errors, and its methods take a context and return errors
+// instead of panicking. The free functions of version 1 are available from
+// package compat for code that is migrated gradually.
+//
+// The generation logic is shared with version 1, so both versions produce
+// secrets with the same distribution for the same configuration.
+package genpass
+
+import (
+	v1 "github.com/calico32/genpass"
+)
+
+// Charset is an immutable set of characters.
+type Charset = v1.Charset
+
+// Class is a class of characters commonly referenced by password rules.
+type Class = v1.Class
+
+const (
+	ClassLower   = v1.ClassLower
+	ClassUpper   = v1.ClassUpper
+	ClassDigit   = v1.ClassDigit
+	ClassSpecial = v1.ClassSpecial
+)
+
+// Strength is a coarse rating of a password's entropy.
+type Strength = v1.Strength
+
+// Policy is a set of password composition rules.
+type Policy = v1.Policy
+
+// Transform modifies the words of a passphrase.
+type Transform = v1.Transform
+
+// Errors that can be detected with errors.Is, see the version 1 documentation
+// for their meaning.
+var (
+	ErrPolicyViolation   = v1.ErrPolicyViolation
+	ErrSinkUnavailable   = v1.ErrSinkUnavailable
+	ErrEntropySource     = v1.ErrEntropySource
+	ErrKeyspaceExhausted = v1.ErrKeyspaceExhausted
+)
+
+// Built-in charsets.
+var (
+	CharsetLower   = v1.NewCharset(v1.CharsetLower)
+	CharsetUpper   = v1.NewCharset(v1.CharsetUpper)
+	CharsetDigit   = v1.NewCharset(v1.CharsetNum)
+	CharsetSpecial = v1.NewCharset(v1.CharsetSpecial)
+	CharsetAll     = v1.NewCharset(v1.CharsetAll)
+)
+
+// NewCharset returns the set of characters in s.
+func NewCharset(s string) Charset {
+	return v1.NewCharset(s)
+}
+
+// ParseCharset evaluates a charset expression such as "alpha+num-ambiguous".
+func ParseCharset(expr string) (Charset, error) {
+	return v1.ParseCharset(expr)
+}
+
+// WordlistEFF is the EFF large wordlist, the default wordlist of passphrases.
+var WordlistEFF = v1.WordlistEFF
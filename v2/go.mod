@@ -0,0 +1,16 @@
+module github.com/calico32/genpass/v2
+
+go 1.24.1
+
+require github.com/calico32/genpass v0.0.0-00010101000000-000000000000
+
+require (
+	filippo.io/age v1.2.1 // indirect
+	filippo.io/edwards25519 v1.1.0 // indirect
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e // indirect
+	golang.org/x/crypto v0.40.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/calico32/genpass => ../
@@ -0,0 +1,161 @@
+package genpass
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultVectorSeed is the seed of the published test vectors.
+const DefaultVectorSeed = "genpass test vectors v1"
+
+// Vector modes.
+const (
+	// VectorConfig vectors generate a password or passphrase from a [Config].
+	VectorConfig = "config"
+	// VectorPattern vectors generate a recovery code from a pattern, as
+	// [GenerateRecoveryCodes] does.
+	VectorPattern = "pattern"
+	// VectorEncoding vectors encode Random with a built-in [Encoder].
+	VectorEncoding = "encoding"
+)
+
+// Vector is a test vector for ports of genpass to other languages: an input,
+// the random bytes it consumes, and the expected output.
+//
+// Config and pattern vectors make their random choices with [NewByteRand]
+// over Random: an integer in [0, n) is read as the smallest number of
+// big-endian bytes that can hold n-1 (at most 4), and values in the final
+// partial multiple of n are skipped. A port that draws its choices the same
+// way must produce Output exactly and consume all of Random.
+type Vector struct {
+	Mode     string  `json:"mode"`
+	Config   *Config `json:"config,omitempty"`
+	Pattern  string  `json:"pattern,omitempty"`
+	Encoding string  `json:"encoding,omitempty"`
+	// Random is the hex-encoded random input.
+	Random string `json:"random"`
+	Output string `json:"output"`
+}
+
+// vectorConfigs are the configurations covered by [Vectors].
+var vectorConfigs = func() []Config {
+	space, dot, none := " ", ".", ""
+	return []Config{
+		{},
+		{Length: 1},
+		{Charset: "hex", Length: 32},
+		{Charset: "num", Length: 12},
+		{Charset: "alpha+num", Length: 20},
+		{Charset: "all-ambiguous", Length: 24},
+		{Charset: "lower", Length: 8, Require: []string{"lower"}},
+		{Charset: "all", Length: 12, Require: []string{"lower", "upper", "digit", "special"}},
+		{Charset: "alphanum", Length: 16, Group: 4},
+		{Charset: "upper+num", Length: 15, Group: 5, GroupSeparator: " "},
+		{Words: 1},
+		{Words: 6},
+		{Words: 4, Separator: &dot},
+		{Words: 5, Separator: &none, Capitalize: true},
+		{Words: 6, Separator: &space, Leet: 0.3},
+		{Words: 4, AddDigit: true},
+		{Words: 7, Capitalize: true, Leet: 0.5, AddDigit: true},
+	}
+}()
+
+// vectorPatterns are the recovery code patterns covered by [Vectors].
+var vectorPatterns = []string{
+	DefaultRecoveryPattern,
+	"xxxx-xxxx-xxxx",
+	"xxxxxxxxxx",
+	"ID-xxx.xxx",
+}
+
+// vectorEncodings are the encoders covered by [Vectors]. Encoders registered
+// by other packages are not part of genpass and have no vectors.
+var vectorEncodings = []string{"base32", "base64", "base64url", "base85", "bubblebabble", "hex", "pgpwords", "proquint"}
+
+// vectorEncodingSizes are the input sizes of encoding vectors.
+var vectorEncodingSizes = []int{2, 16, 32}
+
+// VectorRandom returns the random input of the vector at index i for seed: n
+// bytes made of the SHA-256 hashes of seed followed by i and a block counter,
+// each as 4 big-endian bytes.
+func VectorRandom(seed []byte, i, n int) []byte {
+	out := make([]byte, 0, n+sha256.Size)
+	buf := make([]byte, len(seed)+8)
+	copy(buf, seed)
+	binary.BigEndian.PutUint32(buf[len(seed):], uint32(i))
+	for block := uint32(0); len(out) < n; block++ {
+		binary.BigEndian.PutUint32(buf[len(seed)+4:], block)
+		sum := sha256.Sum256(buf)
+		out = append(out, sum[:]...)
+	}
+	return out[:n]
+}
+
+// vectorRandomSize is the number of random bytes available to config and
+// pattern vectors. Only the bytes they consume are recorded.
+const vectorRandomSize = 1024
+
+// Vectors returns the canonical test vectors for seed. The same seed always
+// produces the same vectors, so a port can check itself against the output
+// of Vectors([]byte(DefaultVectorSeed)) or the vectors command of the genpass
+// CLI.
+func Vectors(seed []byte) ([]Vector, error) {
+	var vectors []Vector
+	for _, c := range vectorConfigs {
+		g, err := c.Generator()
+		if err != nil {
+			return nil, err
+		}
+		v, err := vectorFrom(g, VectorRandom(seed, len(vectors), vectorRandomSize))
+		if err != nil {
+			return nil, fmt.Errorf("genpass: vector for %+v: %w", c, err)
+		}
+		v.Mode, v.Config = VectorConfig, &c
+		vectors = append(vectors, v)
+	}
+
+	for _, pattern := range vectorPatterns {
+		positions, err := recoveryPositions(pattern)
+		if err != nil {
+			return nil, err
+		}
+		v, err := vectorFrom(NewGenerator(WithPositions(positions...)), VectorRandom(seed, len(vectors), vectorRandomSize))
+		if err != nil {
+			return nil, fmt.Errorf("genpass: vector for pattern %q: %w", pattern, err)
+		}
+		v.Mode, v.Pattern = VectorPattern, pattern
+		vectors = append(vectors, v)
+	}
+
+	for _, name := range vectorEncodings {
+		for _, size := range vectorEncodingSizes {
+			random := VectorRandom(seed, len(vectors), size)
+			out, err := EncodeBytes(name, random)
+			if err != nil {
+				return nil, err
+			}
+			vectors = append(vectors, Vector{
+				Mode:     VectorEncoding,
+				Encoding: name,
+				Random:   hex.EncodeToString(random),
+				Output:   out,
+			})
+		}
+	}
+	return vectors, nil
+}
+
+// vectorFrom generates a secret with g from random and returns it with the
+// part of random that was consumed.
+func vectorFrom(g *Generator, random []byte) (Vector, error) {
+	r := &byteRand{data: random}
+	out, err := g.GenerateFrom(r)
+	if err != nil {
+		return Vector{}, err
+	}
+	used := random[:len(random)-len(r.data)]
+	return Vector{Random: hex.EncodeToString(used), Output: out}, nil
+}
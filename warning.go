@@ -0,0 +1,80 @@
+package genpass
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// WarningCode identifies the kind of a [Warning].
+type WarningCode string
+
+const (
+	// WarnDuplicateChars means duplicate characters were removed from the
+	// charset.
+	WarnDuplicateChars WarningCode = "duplicate-chars"
+	// WarnLowEntropy means the configuration provides less entropy than
+	// [RecommendedEntropy].
+	WarnLowEntropy WarningCode = "low-entropy"
+	// WarnLowAcceptance means most candidates are rejected by the required
+	// classes, making generation slow.
+	WarnLowAcceptance WarningCode = "low-acceptance"
+)
+
+// Warning describes a problem with a generator's configuration that doesn't
+// prevent it from generating passwords.
+type Warning struct {
+	Code WarningCode
+	// Message is a human-readable description in the current locale.
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
+
+// RecommendedEntropy is the entropy, in bits, below which [Generator.Warnings]
+// reports [WarnLowEntropy].
+const RecommendedEntropy = minEntropyFair
+
+// lowAcceptanceRate is the fraction of accepted candidates below which
+// [WarnLowAcceptance] is reported.
+const lowAcceptanceRate = 0.01
+
+// Warnings returns the warnings for the generator's configuration.
+func (g *Generator) Warnings() []Warning {
+	loc := CurrentLocale()
+	var warnings []Warning
+
+	if !g.Passphrase() && g.duplicates > 0 {
+		warnings = append(warnings, Warning{
+			Code:    WarnDuplicateChars,
+			Message: loc.N(MsgWarnDuplicateChars, big.NewInt(int64(g.duplicates))),
+		})
+	}
+
+	if e := g.Entropy(); e < RecommendedEntropy {
+		warnings = append(warnings, Warning{
+			Code:    WarnLowEntropy,
+			Message: fmt.Sprintf(loc.T(MsgWarnLowEntropy), e, RecommendedEntropy),
+		})
+	}
+
+	if rate := g.AcceptanceRate(); rate > 0 && rate < lowAcceptanceRate {
+		warnings = append(warnings, Warning{
+			Code:    WarnLowAcceptance,
+			Message: fmt.Sprintf(loc.T(MsgWarnLowAcceptance), rate*100),
+		})
+	}
+
+	return warnings
+}
+
+// GenerateWithWarnings is like [Generator.Generate] but also returns the
+// warnings for the generator's configuration.
+func (g *Generator) GenerateWithWarnings() (string, []Warning, error) {
+	password, err := g.Generate()
+	if err != nil {
+		return "", nil, err
+	}
+	return password, g.Warnings(), nil
+}
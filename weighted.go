@@ -0,0 +1,121 @@
+package genpass
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// LoadWeightedWordlist reads a frequency-weighted wordlist from r, one word per
+// line followed by its weight, e.g. "the 23135851162". Weights are positive
+// integers, typically occurrence counts from a corpus; a word is chosen with
+// probability proportional to its weight. Blank lines and lines starting with
+// # are ignored. Words are normalized and deduplicated like in [LoadWordlist],
+// and the weights of repeated words are added together. The words are
+// returned in the same order as LoadWordlist returns them, with their
+// weights.
+func LoadWeightedWordlist(r io.Reader) ([]string, []int, error) {
+	weights := map[string]int{}
+	spelling := map[string]string{}
+	fold := cases.Fold()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !utf8.ValidString(line) {
+			return nil, nil, errors.New("genpass: wordlist is not valid UTF-8")
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("genpass: invalid weighted wordlist line %q", line)
+		}
+		w, err := strconv.Atoi(fields[1])
+		if err != nil || w <= 0 {
+			return nil, nil, fmt.Errorf("genpass: invalid weight in wordlist line %q", line)
+		}
+		word := norm.NFC.String(fields[0])
+		key := wordKey(fold, word)
+		if _, ok := spelling[key]; !ok {
+			spelling[key] = word
+		}
+		word = spelling[key]
+		if weights[word] > math.MaxInt-w {
+			return nil, nil, errors.New("genpass: wordlist weights are too large")
+		}
+		weights[word] += w
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	words := make([]string, 0, len(weights))
+	for word := range weights {
+		words = append(words, word)
+	}
+	sortWords(words)
+	if len(words) < 2 {
+		return nil, nil, fmt.Errorf("genpass: wordlist must contain at least 2 distinct words, got %d", len(words))
+	}
+	ws := make([]int, len(words))
+	for i, word := range words {
+		ws[i] = weights[word]
+	}
+	return words, ws, nil
+}
+
+// ShannonEntropy returns the Shannon entropy, in bits, of choosing an item
+// with probability proportional to its weight.
+func ShannonEntropy(weights []int) float64 {
+	total := 0.0
+	for _, w := range weights {
+		total += float64(w)
+	}
+	e := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			p := float64(w) / total
+			e -= p * math.Log2(p)
+		}
+	}
+	return e
+}
+
+// multisetEntropy returns the entropy, in bits, of choosing one of items
+// uniformly at random, where items that appear more than once are
+// proportionally more likely to come up.
+func multisetEntropy[T comparable](items []T) float64 {
+	counts := make(map[T]int, len(items))
+	for _, item := range items {
+		counts[item]++
+	}
+	if len(counts) == len(items) {
+		return math.Log2(float64(len(items)))
+	}
+	weights := make([]int, 0, len(counts))
+	for _, c := range counts {
+		weights = append(weights, c)
+	}
+	return ShannonEntropy(weights)
+}
+
+// wordlistEntropy is like multisetEntropy for wordlists, but skips counting
+// the words of the built-in wordlists, which have no duplicates, since
+// generators are often created for every passphrase.
+func wordlistEntropy(words []string) float64 {
+	for _, builtin := range [][]string{WordlistEFF, WordlistBIP39} {
+		if len(words) == len(builtin) && len(words) > 0 && &words[0] == &builtin[0] {
+			return math.Log2(float64(len(words)))
+		}
+	}
+	return multisetEntropy(words)
+}
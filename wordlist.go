@@ -0,0 +1,15 @@
+package genpass
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed wordlists/eff_large.txt
+var effLarge string
+
+// WordlistEFF is the EFF large wordlist of 7776 words, designed for generating
+// passphrases with dice.
+//
+// See https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases.
+var WordlistEFF = strings.Fields(effLarge)